@@ -5,12 +5,12 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
-	"github.com/avito/pr-reviewer-service/internal/database"
 	"github.com/avito/pr-reviewer-service/internal/handler"
-	"github.com/avito/pr-reviewer-service/internal/repository"
+	"github.com/avito/pr-reviewer-service/internal/middleware"
 	"github.com/avito/pr-reviewer-service/internal/router"
 	"github.com/avito/pr-reviewer-service/internal/service"
 	"github.com/rs/zerolog"
@@ -21,29 +21,69 @@ func main() {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 	zerolog.SetGlobalLevel(zerolog.InfoLevel)
 
-	db, err := database.NewDB()
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(os.Args[2:]); err != nil {
+			log.Fatal().Err(err).Msg("Migration failed")
+		}
+		return
+	}
+
+	storageKind := os.Getenv("STORAGE")
+	store, err := newStorage(storageKind)
 	if err != nil {
-		log.Fatal().Err(err).Msg("Failed to connect to database")
+		log.Fatal().Err(err).Msg("Failed to initialize storage")
+	}
+	defer store.Close() //nolint:errcheck
+	if storageKind == storageMemory {
+		log.Warn().Msg("Using in-memory storage, data will be lost on restart")
+	}
+	if value := os.Getenv("MIGRATE_ON_START"); value != "" && store.db != nil {
+		enabled, parseErr := strconv.ParseBool(value)
+		if parseErr != nil {
+			log.Fatal().Err(parseErr).Str("value", value).Msg("Invalid MIGRATE_ON_START")
+		}
+		if enabled {
+			if err = autoMigrate(store.db); err != nil {
+				log.Fatal().Err(err).Msg("Failed to apply migrations")
+			}
+		}
 	}
-	defer db.Close() //nolint:errcheck
 
-	teamRepo := repository.NewTeamRepository(db)
-	userRepo := repository.NewUserRepository(db)
-	prRepo := repository.NewPullRequestRepository(db)
+	strategy, err := service.ParseSelectionStrategy(os.Getenv("REVIEWER_STRATEGY"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid REVIEWER_STRATEGY")
+	}
+	teamStrategies, err := service.ParseTeamStrategies(os.Getenv("TEAM_REVIEWER_STRATEGIES"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid TEAM_REVIEWER_STRATEGIES")
+	}
+	selectors := service.NewReviewerSelectors(strategy, teamStrategies)
 
-	teamService := service.NewTeamService(teamRepo, userRepo)
-	userService := service.NewUserService(userRepo)
-	prService := service.NewPRService(prRepo, userRepo)
-	statsService := service.NewStatsService(prRepo)
+	prService := service.NewPRService(store.prRepo, store.userRepo, store.teamRepo, selectors)
+	teamService := service.NewTeamService(store.teamRepo, store.userRepo, prService, store.txManager)
+	userService := service.NewUserService(store.userRepo, prService, store.txManager)
+	absenceService := service.NewAbsenceService(store.absenceRepo, store.userRepo)
+	statsService := service.NewStatsService(store.prRepo)
 
 	teamHandler := handler.NewTeamHandler(teamService)
 	userHandler := handler.NewUserHandler(userService, prService)
-	prHandler := handler.NewPRHandler(prService)
+	absenceHandler := handler.NewAbsenceHandler(absenceService)
+	prHandler := handler.NewPRHandler(prService, os.Getenv("ADMIN_TOKEN"))
 	statsHandler := handler.NewStatsHandler(statsService)
-	healthHandler := handler.NewHealthHandler(db)
+	healthHandler := handler.NewHealthHandler(store.db)
 	metricsHandler := handler.NewMetricsHandler()
 
-	r := router.SetupRouter(teamHandler, userHandler, prHandler, statsHandler, healthHandler, metricsHandler)
+	idempotencyTTL := 24 * time.Hour
+	if value := os.Getenv("IDEMPOTENCY_TTL"); value != "" {
+		idempotencyTTL, err = time.ParseDuration(value)
+		if err != nil || idempotencyTTL <= 0 {
+			log.Fatal().Err(err).Str("value", value).Msg("Invalid IDEMPOTENCY_TTL")
+		}
+	}
+	go cleanupIdempotencyKeys(store.idempotency, time.Hour)
+
+	r := router.SetupRouter(teamHandler, userHandler, absenceHandler, prHandler, statsHandler, healthHandler, metricsHandler,
+		middleware.Idempotency(store.idempotency, idempotencyTTL))
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -79,4 +119,22 @@ func main() {
 	}
 
 	log.Info().Msg("Server exited")
-}
\ No newline at end of file
+}
+
+func cleanupIdempotencyKeys(repo idempotencyRepository, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		deleted, err := repo.DeleteExpired(ctx)
+		cancel()
+		if err != nil {
+			log.Error().Err(err).Msg("Failed to delete expired idempotency keys")
+			continue
+		}
+		if deleted > 0 {
+			log.Info().Int64("deleted", deleted).Msg("Expired idempotency keys deleted")
+		}
+	}
+}
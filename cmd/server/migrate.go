@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/avito/pr-reviewer-service/internal/database"
+	"github.com/rs/zerolog/log"
+)
+
+const autoMigrateTimeout = 5 * time.Minute
+
+var errMigrateUsage = errors.New("usage: server migrate up | down [N|all] | status | force VERSION")
+
+func runMigrate(args []string) error {
+	if len(args) == 0 {
+		return errMigrateUsage
+	}
+
+	db, err := database.NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close() //nolint:errcheck
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	switch args[0] {
+	case "up":
+		applied, upErr := migrator.Up(ctx)
+		logMigrations(applied, "Migration applied")
+		if upErr == nil && len(applied) == 0 {
+			log.Info().Msg("No pending migrations")
+		}
+		return upErr
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = parseSteps(args[1]); err != nil {
+				return err
+			}
+		}
+		reverted, downErr := migrator.Down(ctx, steps)
+		logMigrations(reverted, "Migration reverted")
+		return downErr
+	case "status":
+		statuses, statusErr := migrator.Status(ctx)
+		if statusErr != nil {
+			return statusErr
+		}
+		printMigrationStatus(statuses)
+		return nil
+	case "force":
+		if len(args) < 2 {
+			return errMigrateUsage
+		}
+		version, parseErr := strconv.ParseInt(args[1], 10, 64)
+		if parseErr != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		if err = migrator.Force(ctx, version); err != nil {
+			return err
+		}
+		log.Info().Int64("version", version).Msg("Migration version forced")
+		return nil
+	default:
+		return errMigrateUsage
+	}
+}
+
+func autoMigrate(db *sql.DB) error {
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), autoMigrateTimeout)
+	defer cancel()
+
+	applied, err := migrator.Up(ctx)
+	logMigrations(applied, "Migration applied")
+	return err
+}
+
+func newMigrator(db *sql.DB) (*database.Migrator, error) {
+	driver := database.Driver()
+	fsys, err := database.EmbeddedMigrations(driver)
+	if err != nil {
+		return nil, err
+	}
+	return database.NewMigrator(db, driver, fsys)
+}
+
+func parseSteps(value string) (int, error) {
+	if value == "all" {
+		return math.MaxInt, nil
+	}
+	steps, err := strconv.Atoi(value)
+	if err != nil || steps <= 0 {
+		return 0, fmt.Errorf("invalid number of steps %q", value)
+	}
+	return steps, nil
+}
+
+func logMigrations(migrations []database.Migration, message string) {
+	for _, migration := range migrations {
+		log.Info().Int64("version", migration.Version).Str("name", migration.Name).Msg(message)
+	}
+}
+
+func printMigrationStatus(statuses []database.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE\tAPPLIED AT") //nolint:errcheck
+	for _, status := range statuses {
+		state := "pending"
+		switch {
+		case status.Missing:
+			state = "missing"
+		case status.Modified:
+			state = "modified"
+		case status.Applied:
+			state = "applied"
+		}
+		appliedAt := "-"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Version, status.Name, state, appliedAt) //nolint:errcheck
+	}
+	w.Flush() //nolint:errcheck
+}
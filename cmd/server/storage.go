@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/avito/pr-reviewer-service/internal/database"
+	"github.com/avito/pr-reviewer-service/internal/middleware"
+	"github.com/avito/pr-reviewer-service/internal/repository"
+	"github.com/avito/pr-reviewer-service/internal/service"
+)
+
+const (
+	storagePostgres = "postgres"
+	storageMemory   = "memory"
+)
+
+type idempotencyRepository interface {
+	middleware.IdempotencyStore
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type storage struct {
+	db          *sql.DB
+	teamRepo    service.TeamRepositoryInterface
+	userRepo    service.UserRepositoryInterface
+	prRepo      service.PRRepositoryInterface
+	absenceRepo service.AbsenceRepositoryInterface
+	txManager   service.TxManagerInterface
+	idempotency idempotencyRepository
+}
+
+func newStorage(kind string) (*storage, error) {
+	switch kind {
+	case "", storagePostgres:
+		db, err := database.NewDB()
+		if err != nil {
+			return nil, err
+		}
+		return &storage{
+			db:          db,
+			teamRepo:    repository.NewTeamRepository(db),
+			userRepo:    repository.NewUserRepository(db),
+			prRepo:      repository.NewPullRequestRepository(db),
+			absenceRepo: repository.NewAbsenceRepository(db),
+			txManager:   repository.NewTxManager(db),
+			idempotency: repository.NewIdempotencyRepository(db),
+		}, nil
+	case storageMemory:
+		store := repository.NewMemoryStore()
+		return &storage{
+			teamRepo:    repository.NewMemoryTeamRepository(store),
+			userRepo:    repository.NewMemoryUserRepository(store),
+			prRepo:      repository.NewMemoryPullRequestRepository(store),
+			absenceRepo: repository.NewMemoryAbsenceRepository(store),
+			txManager:   repository.NewMemoryTxManager(store),
+			idempotency: repository.NewMemoryIdempotencyRepository(store),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q, expected %q or %q", kind, storagePostgres, storageMemory)
+	}
+}
+
+func (s *storage) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.Close()
+}
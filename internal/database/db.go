@@ -4,12 +4,34 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
+	"net/url"
 	"os"
 
 	_ "github.com/lib/pq"
+	_ "github.com/mattn/go-sqlite3"
 )
 
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+func Driver() string {
+	return getEnv("DB_DRIVER", DriverPostgres)
+}
+
 func NewDB() (*sql.DB, error) {
+	switch driver := Driver(); driver {
+	case DriverPostgres:
+		return newPostgresDB()
+	case DriverSQLite:
+		return newSQLiteDB()
+	default:
+		return nil, fmt.Errorf("unknown DB_DRIVER %q, expected %q or %q", driver, DriverPostgres, DriverSQLite)
+	}
+}
+
+func newPostgresDB() (*sql.DB, error) {
 	host := getEnv("DB_HOST", "localhost")
 	port := getEnv("DB_PORT", "5432")
 	user := getEnv("DB_USER", "postgres")
@@ -36,6 +58,30 @@ func NewDB() (*sql.DB, error) {
 	return db, nil
 }
 
+func newSQLiteDB() (*sql.DB, error) {
+	path := getEnv("DB_PATH", "pr_reviewer.db")
+
+	params := url.Values{}
+	params.Set("_foreign_keys", "on")
+	params.Set("_journal_mode", "WAL")
+	params.Set("_busy_timeout", "5000")
+	params.Set("_txlock", "immediate")
+
+	db, err := sql.Open("sqlite3", "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	db.SetMaxOpenConns(1)
+
+	log.Printf("SQLite database opened at %s", path)
+	return db, nil
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -0,0 +1,424 @@
+package database
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/avito/pr-reviewer-service/migrations"
+)
+
+const migrationLockID int64 = 4_172_983_611
+
+const createSchemaMigrations = `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		checksum VARCHAR(64) NOT NULL,
+		applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`
+
+var (
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+	ErrDirtyMigration   = errors.New("schema_migrations is dirty")
+	ErrUnknownMigration = errors.New("unknown migration version")
+)
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+	Modified  bool
+	Missing   bool
+}
+
+type appliedMigration struct {
+	name      string
+	checksum  string
+	appliedAt time.Time
+}
+
+type Migrator struct {
+	db         *sql.DB
+	driver     string
+	migrations []Migration
+}
+
+func EmbeddedMigrations(driver string) (fs.FS, error) {
+	switch driver {
+	case DriverPostgres:
+		return migrations.FS, nil
+	case DriverSQLite:
+		return fs.Sub(migrations.FS, "sqlite")
+	default:
+		return nil, fmt.Errorf("no migrations for driver %q", driver)
+	}
+}
+
+func LoadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, parseErr := strconv.ParseInt(match[1], 10, 64)
+		if parseErr != nil || version <= 0 {
+			return nil, fmt.Errorf("invalid migration version in %s", entry.Name())
+		}
+		content, readErr := fs.ReadFile(fsys, entry.Name())
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), readErr)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has conflicting names %q and %q", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			sum := sha256.Sum256(content)
+			migration.Up = string(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Checksum == "" {
+			return nil, fmt.Errorf("migration %d (%s) has no up file", migration.Version, migration.Name)
+		}
+		result = append(result, *migration)
+	}
+	slices.SortFunc(result, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	return result, nil
+}
+
+func NewMigrator(db *sql.DB, driver string, fsys fs.FS) (*Migrator, error) {
+	loaded, err := LoadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, driver: driver, migrations: loaded}, nil
+}
+
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, false, func(conn *sql.Conn, done map[int64]appliedMigration) error {
+		if err := m.verifyChecksums(done); err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if _, ok := done[migration.Version]; ok {
+				continue
+			}
+			if err := m.apply(ctx, conn, migration); err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, false, func(conn *sql.Conn, done map[int64]appliedMigration) error {
+		versions := make([]int64, 0, len(done))
+		for version := range done {
+			versions = append(versions, version)
+		}
+		slices.Sort(versions)
+		slices.Reverse(versions)
+
+		for _, version := range versions[:min(steps, len(versions))] {
+			migration, ok := m.find(version)
+			if !ok {
+				return fmt.Errorf("cannot revert migration %d: %w", version, ErrUnknownMigration)
+			}
+			if err := m.revert(ctx, conn, migration); err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+func (m *Migrator) Force(ctx context.Context, version int64) error {
+	if _, ok := m.find(version); !ok && version != 0 {
+		return fmt.Errorf("cannot force version %d: %w", version, ErrUnknownMigration)
+	}
+
+	return m.withLock(ctx, true, func(conn *sql.Conn, _ map[int64]appliedMigration) error {
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		defer tx.Rollback() //nolint:errcheck
+
+		if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version > $1`, version); err != nil {
+			return fmt.Errorf("failed to delete migrations above %d: %w", version, err)
+		}
+		for _, migration := range m.migrations {
+			if migration.Version > version {
+				break
+			}
+			result, err := tx.ExecContext(ctx,
+				`UPDATE schema_migrations SET name = $1, checksum = $2 WHERE version = $3`,
+				migration.Name, migration.Checksum, migration.Version)
+			if err != nil {
+				return fmt.Errorf("failed to force migration %d: %w", migration.Version, err)
+			}
+			if rows, _ := result.RowsAffected(); rows > 0 {
+				continue
+			}
+			if err := recordMigration(ctx, tx, migration); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	})
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, false, func(_ *sql.Conn, done map[int64]appliedMigration) error {
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Version: migration.Version, Name: migration.Name}
+			if record, ok := done[migration.Version]; ok {
+				status.Applied = true
+				status.AppliedAt = &record.appliedAt
+				status.Modified = record.checksum != migration.Checksum
+			}
+			statuses = append(statuses, status)
+		}
+		for version, record := range done {
+			if _, ok := m.find(version); !ok {
+				statuses = append(statuses, MigrationStatus{
+					Version:   version,
+					Name:      record.name,
+					Applied:   true,
+					AppliedAt: &record.appliedAt,
+					Missing:   true,
+				})
+			}
+		}
+		slices.SortFunc(statuses, func(a, b MigrationStatus) int {
+			return cmp.Compare(a.Version, b.Version)
+		})
+		return nil
+	})
+	return statuses, err
+}
+
+func (m *Migrator) find(version int64) (Migration, bool) {
+	index, found := slices.BinarySearchFunc(m.migrations, version, func(migration Migration, target int64) int {
+		return cmp.Compare(migration.Version, target)
+	})
+	if !found {
+		return Migration{}, false
+	}
+	return m.migrations[index], true
+}
+
+func (m *Migrator) verifyChecksums(done map[int64]appliedMigration) error {
+	for _, migration := range m.migrations {
+		if record, ok := done[migration.Version]; ok && record.checksum != migration.Checksum {
+			return fmt.Errorf("migration %d (%s) changed after it was applied, run `migrate force %d` once the schema matches: %w",
+				migration.Version, migration.Name, migration.Version, ErrChecksumMismatch)
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+		return fmt.Errorf("failed to apply migration %d (%s): %w", migration.Version, migration.Name, err)
+	}
+	if err := recordMigration(ctx, tx, migration); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	if migration.Down == "" {
+		return fmt.Errorf("migration %d (%s) has no down file", migration.Version, migration.Name)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+		return fmt.Errorf("failed to revert migration %d (%s): %w", migration.Version, migration.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version); err != nil {
+		return fmt.Errorf("failed to unrecord migration %d: %w", migration.Version, err)
+	}
+	return tx.Commit()
+}
+
+func recordMigration(ctx context.Context, tx *sql.Tx, migration Migration) error {
+	_, err := tx.ExecContext(ctx,
+		`INSERT INTO schema_migrations (version, name, checksum) VALUES ($1, $2, $3)`,
+		migration.Version, migration.Name, migration.Checksum)
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", migration.Version, err)
+	}
+	return nil
+}
+
+func (m *Migrator) withLock(ctx context.Context, force bool, fn func(conn *sql.Conn, done map[int64]appliedMigration) error) (err error) {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close() //nolint:errcheck
+
+	if m.driver == DriverPostgres {
+		if _, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockID); err != nil {
+			return fmt.Errorf("failed to acquire migration lock: %w", err)
+		}
+		defer func() {
+			unlockCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if _, unlockErr := conn.ExecContext(unlockCtx, `SELECT pg_advisory_unlock($1)`, migrationLockID); unlockErr != nil {
+				conn.Raw(func(interface{}) error { return driver.ErrBadConn }) //nolint:errcheck
+				if err == nil {
+					err = fmt.Errorf("failed to release migration lock: %w", unlockErr)
+				}
+			}
+		}()
+	}
+
+	if err = m.ensureTable(ctx, conn, force); err != nil {
+		return err
+	}
+	done, err := loadApplied(ctx, conn)
+	if err != nil {
+		return err
+	}
+	return fn(conn, done)
+}
+
+func (m *Migrator) ensureTable(ctx context.Context, conn *sql.Conn, force bool) error {
+	rows, err := conn.QueryContext(ctx, `SELECT * FROM schema_migrations LIMIT 0`)
+	if err == nil {
+		columns, columnsErr := rows.Columns()
+		rows.Close() //nolint:errcheck
+		if columnsErr != nil {
+			return fmt.Errorf("failed to inspect schema_migrations: %w", columnsErr)
+		}
+		if slices.Contains(columns, "dirty") && !slices.Contains(columns, "checksum") {
+			return m.adoptLegacyTable(ctx, conn, force)
+		}
+	}
+
+	_, err = conn.ExecContext(ctx, createSchemaMigrations)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return nil
+}
+
+func (m *Migrator) adoptLegacyTable(ctx context.Context, conn *sql.Conn, force bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var version int64
+	var dirty bool
+	err = tx.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to read legacy schema_migrations: %w", err)
+	}
+	if dirty && !force {
+		return fmt.Errorf("version %d, fix the schema and run `migrate force`: %w", version, ErrDirtyMigration)
+	}
+	if dirty {
+		version--
+	}
+
+	if _, err = tx.ExecContext(ctx, `DROP TABLE schema_migrations`); err != nil {
+		return fmt.Errorf("failed to drop legacy schema_migrations: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, createSchemaMigrations)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	for _, migration := range m.migrations {
+		if migration.Version > version {
+			break
+		}
+		if err := recordMigration(ctx, tx, migration); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func loadApplied(ctx context.Context, conn *sql.Conn) (map[int64]appliedMigration, error) {
+	rows, err := conn.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	done := make(map[int64]appliedMigration)
+	for rows.Next() {
+		var version int64
+		var record appliedMigration
+		if err := rows.Scan(&version, &record.name, &record.checksum, &record.appliedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan schema_migrations: %w", err)
+		}
+		done[version] = record
+	}
+	return done, rows.Err()
+}
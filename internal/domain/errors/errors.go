@@ -0,0 +1,103 @@
+package errors
+
+import "net/http"
+
+const (
+	CodeTeamExists         = "TEAM_EXISTS"
+	CodePRExists           = "PR_EXISTS"
+	CodePRMerged           = "PR_MERGED"
+	CodeNotAssigned        = "NOT_ASSIGNED"
+	CodeNoCandidate        = "NO_CANDIDATE"
+	CodeNotFound           = "NOT_FOUND"
+	CodeInvalidRequest     = "INVALID_REQUEST"
+	CodeForbidden          = "FORBIDDEN"
+	CodeMergeBlocked       = "MERGE_BLOCKED"
+	CodeInvalidTransition  = "INVALID_TRANSITION"
+	CodePRNotOpen          = "PR_NOT_OPEN"
+	CodeCapacityExhausted  = "CAPACITY_EXHAUSTED"
+	CodeWrongTeam          = "WRONG_TEAM"
+	CodeIdempotencyReused  = "IDEMPOTENCY_KEY_REUSED"
+	CodeIdempotencyPending = "IDEMPOTENCY_IN_PROGRESS"
+	CodeTimeout            = "TIMEOUT"
+	CodeInternal           = "INTERNAL_ERROR"
+)
+
+type Error struct {
+	Code    string
+	Status  int
+	Message string
+	Details []string
+	base    *Error
+}
+
+func New(code string, status int, message string) *Error {
+	return &Error{Code: code, Status: status, Message: message}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && e.base != nil && e.base == t
+}
+
+func (e *Error) WithMessage(message string) *Error {
+	derived := e.derive()
+	derived.Message = message
+	return derived
+}
+
+func (e *Error) WithDetails(details []string) *Error {
+	derived := e.derive()
+	derived.Details = details
+	return derived
+}
+
+func (e *Error) derive() *Error {
+	base := e
+	if e.base != nil {
+		base = e.base
+	}
+	return &Error{Code: e.Code, Status: e.Status, Message: e.Message, Details: e.Details, base: base}
+}
+
+var (
+	ErrTeamExists   = New(CodeTeamExists, http.StatusBadRequest, "team_name already exists")
+	ErrPRExists     = New(CodePRExists, http.StatusConflict, "PR id already exists")
+	ErrPRMerged     = New(CodePRMerged, http.StatusConflict, "cannot reassign on merged PR")
+	ErrReviewMerged = New(CodePRMerged, http.StatusConflict, "cannot review merged PR")
+	ErrNotAssigned  = New(CodeNotAssigned, http.StatusConflict, "reviewer is not assigned to this PR")
+	ErrNoCandidate  = New(CodeNoCandidate, http.StatusConflict, "no active replacement candidate in team")
+
+	ErrTeamNotFound    = New(CodeNotFound, http.StatusNotFound, "team not found")
+	ErrUserNotFound    = New(CodeNotFound, http.StatusNotFound, "user not found")
+	ErrAuthorNotFound  = New(CodeNotFound, http.StatusNotFound, "author not found")
+	ErrPRNotFound      = New(CodeNotFound, http.StatusNotFound, "PR not found")
+	ErrAbsenceNotFound = New(CodeNotFound, http.StatusNotFound, "absence not found")
+
+	ErrInvalidStrategy      = New(CodeInvalidRequest, http.StatusBadRequest, "unknown selection_strategy")
+	ErrInvalidAbsenceWindow = New(CodeInvalidRequest, http.StatusBadRequest, "ends_at must be after starts_at")
+	ErrInvalidStatusFilter  = New(CodeInvalidRequest, http.StatusBadRequest, "invalid status filter")
+	ErrInvalidCursor        = New(CodeInvalidRequest, http.StatusBadRequest, "invalid cursor")
+
+	ErrForbidden         = New(CodeForbidden, http.StatusForbidden, "force merge requires a valid X-Admin-Token")
+	ErrMergeBlocked      = New(CodeMergeBlocked, http.StatusConflict, "merge policy requirements are not met")
+	ErrInvalidTransition = New(CodeInvalidTransition, http.StatusConflict, "invalid status transition")
+	ErrPRNotOpen         = New(CodePRNotOpen, http.StatusConflict, "PR is not open")
+
+	ErrCapacityExhausted   = New(CodeCapacityExhausted, http.StatusConflict, "all candidate reviewers are at capacity")
+	ErrReviewerAtCapacity  = New(CodeCapacityExhausted, http.StatusConflict, "reviewer is at capacity")
+	ErrAlreadyAssigned     = New(CodeNoCandidate, http.StatusConflict, "reviewer is already assigned to this PR")
+	ErrAuthorCannotReview  = New(CodeNoCandidate, http.StatusConflict, "author cannot review own PR")
+	ErrReviewerInactive    = New(CodeNoCandidate, http.StatusConflict, "reviewer is not active")
+	ErrReviewerUnavailable = New(CodeNoCandidate, http.StatusConflict, "reviewer is absent")
+	ErrWrongTeam           = New(CodeWrongTeam, http.StatusConflict, "new reviewer must be in the replaced reviewer's team")
+
+	ErrTimeout = New(CodeTimeout, http.StatusGatewayTimeout, "operation timed out")
+
+	ErrInvalidIdempotencyKey = New(CodeInvalidRequest, http.StatusBadRequest, "Idempotency-Key must be 1-255 characters")
+	ErrIdempotencyKeyReused  = New(CodeIdempotencyReused, http.StatusUnprocessableEntity, "Idempotency-Key was already used with a different request")
+	ErrIdempotencyInProgress = New(CodeIdempotencyPending, http.StatusConflict, "a request with this Idempotency-Key is still in progress")
+)
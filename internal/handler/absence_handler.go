@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+	"github.com/gin-gonic/gin"
+)
+
+type AbsenceHandler struct {
+	absenceService AbsenceServiceInterface
+}
+
+func NewAbsenceHandler(absenceService AbsenceServiceInterface) *AbsenceHandler {
+	return &AbsenceHandler{absenceService: absenceService}
+}
+
+func (h *AbsenceHandler) CreateAbsence(c *gin.Context) {
+	var req struct {
+		UserID   string    `json:"user_id" binding:"required"`
+		StartsAt time.Time `json:"starts_at" binding:"required"`
+		EndsAt   time.Time `json:"ends_at" binding:"required"`
+		Reason   string    `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingError(c, &req, err)
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	absence, err := h.absenceService.CreateAbsence(ctx, &models.Absence{
+		UserID:   req.UserID,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Reason:   req.Reason,
+	})
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"absence": absence})
+}
+
+func (h *AbsenceHandler) GetAbsences(c *gin.Context) {
+	userID := c.Query("user_id")
+	if userID == "" {
+		invalidParam(c, "user_id", "required", "user_id is required")
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	absences, err := h.absenceService.GetAbsences(ctx, userID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"user_id":  userID,
+		"absences": absences,
+	})
+}
+
+func (h *AbsenceHandler) DeleteAbsence(c *gin.Context) {
+	absenceID, err := strconv.ParseInt(c.Query("absence_id"), 10, 64)
+	if err != nil {
+		invalidParam(c, "absence_id", "numeric", "absence_id must be an integer")
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	if err := h.absenceService.DeleteAbsence(ctx, absenceID); err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "absence deleted successfully"})
+}
@@ -0,0 +1,17 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	operationTimeout     = 5 * time.Second
+	bulkOperationTimeout = 12 * time.Second
+)
+
+func operationContext(c *gin.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(c.Request.Context(), timeout)
+}
@@ -1,17 +1,27 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
+	domainerrors "github.com/avito/pr-reviewer-service/internal/domain/errors"
 	"github.com/avito/pr-reviewer-service/internal/models"
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
 )
 
 func errorResponse(c *gin.Context, code int, errorCode, message string) {
+	if wantsProblem(c) {
+		writeProblem(c, code, errorCode, message, nil, nil)
+		return
+	}
+
 	c.JSON(code, models.ErrorResponse{
 		Error: struct {
-			Code    string `json:"code"`
-			Message string `json:"message"`
+			Code    string   `json:"code"`
+			Message string   `json:"message"`
+			Details []string `json:"details,omitempty"`
 		}{
 			Code:    errorCode,
 			Message: message,
@@ -19,27 +29,49 @@ func errorResponse(c *gin.Context, code int, errorCode, message string) {
 	})
 }
 
+func errorResponseWithDetails(c *gin.Context, code int, errorCode, message string, details []string) {
+	if wantsProblem(c) {
+		writeProblem(c, code, errorCode, message, details, nil)
+		return
+	}
+
+	var response models.ErrorResponse
+	response.Error.Code = errorCode
+	response.Error.Message = message
+	response.Error.Details = details
+	c.JSON(code, response)
+}
+
+const statusClientClosedRequest = 499
+
+func AbortWithError(c *gin.Context, err error) {
+	handleError(c, err)
+	c.Abort()
+}
+
 func handleError(c *gin.Context, err error) {
-	switch err.Error() {
-	case "team already exists":
-		errorResponse(c, http.StatusBadRequest, "TEAM_EXISTS", "team_name already exists")
-	case "team not found":
-		errorResponse(c, http.StatusNotFound, "NOT_FOUND", "team not found")
-	case "user not found":
-		errorResponse(c, http.StatusNotFound, "NOT_FOUND", "user not found")
-	case "PR already exists":
-		errorResponse(c, http.StatusConflict, "PR_EXISTS", "PR id already exists")
-	case "PR not found":
-		errorResponse(c, http.StatusNotFound, "NOT_FOUND", "PR not found")
-	case "author not found":
-		errorResponse(c, http.StatusNotFound, "NOT_FOUND", "author not found")
-	case "cannot reassign on merged PR":
-		errorResponse(c, http.StatusConflict, "PR_MERGED", "cannot reassign on merged PR")
-	case "reviewer is not assigned to this PR":
-		errorResponse(c, http.StatusConflict, "NOT_ASSIGNED", "reviewer is not assigned to this PR")
-	case "no active replacement candidate in team":
-		errorResponse(c, http.StatusConflict, "NO_CANDIDATE", "no active replacement candidate in team")
-	default:
-		errorResponse(c, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error())
+	if errors.Is(err, context.Canceled) && c.Request.Context().Err() != nil {
+		log.Warn().
+			Str("request_id", c.GetString("request_id")).
+			Str("path", c.Request.URL.Path).
+			Msg("Client closed request")
+		c.AbortWithStatus(statusClientClosedRequest)
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = domainerrors.ErrTimeout
 	}
+
+	var domainErr *domainerrors.Error
+	if errors.As(err, &domainErr) {
+		errorResponseWithDetails(c, domainErr.Status, domainErr.Code, domainErr.Message, domainErr.Details)
+		return
+	}
+
+	log.Error().
+		Err(err).
+		Str("request_id", c.GetString("request_id")).
+		Str("path", c.Request.URL.Path).
+		Msg("Unhandled error")
+	errorResponse(c, http.StatusInternalServerError, domainerrors.CodeInternal, "internal server error")
 }
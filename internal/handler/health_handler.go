@@ -24,7 +24,7 @@ func (h *HealthHandler) HealthCheck(c *gin.Context) {
 	}
 
 	if h.db != nil {
-		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
 		defer cancel()
 
 		if err := h.db.PingContext(ctx); err != nil {
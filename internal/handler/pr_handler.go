@@ -1,18 +1,22 @@
 package handler
 
 import (
+	"context"
+	"crypto/subtle"
 	"net/http"
 
-	"github.com/avito/pr-reviewer-service/internal/service"
+	domainerrors "github.com/avito/pr-reviewer-service/internal/domain/errors"
+	"github.com/avito/pr-reviewer-service/internal/models"
 	"github.com/gin-gonic/gin"
 )
 
 type PRHandler struct {
-	prService PRServiceInterface
+	prService  PRServiceInterface
+	adminToken string
 }
 
-func NewPRHandler(prService *service.PRService) *PRHandler {
-	return &PRHandler{prService: prService}
+func NewPRHandler(prService PRServiceInterface, adminToken string) *PRHandler {
+	return &PRHandler{prService: prService, adminToken: adminToken}
 }
 
 func (h *PRHandler) CreatePR(c *gin.Context) {
@@ -20,13 +24,17 @@ func (h *PRHandler) CreatePR(c *gin.Context) {
 		PullRequestID   string `json:"pull_request_id" binding:"required"`
 		PullRequestName string `json:"pull_request_name" binding:"required"`
 		AuthorID        string `json:"author_id" binding:"required"`
+		Draft           bool   `json:"draft"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		errorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		bindingError(c, &req, err)
 		return
 	}
 
-	pr, err := h.prService.CreatePR(req.PullRequestID, req.PullRequestName, req.AuthorID)
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	pr, err := h.prService.CreatePR(ctx, req.PullRequestID, req.PullRequestName, req.AuthorID, req.Draft)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -38,13 +46,55 @@ func (h *PRHandler) CreatePR(c *gin.Context) {
 func (h *PRHandler) MergePR(c *gin.Context) {
 	var req struct {
 		PullRequestID string `json:"pull_request_id" binding:"required"`
+		Force         bool   `json:"force"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		errorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		bindingError(c, &req, err)
+		return
+	}
+
+	if req.Force && !h.isAdmin(c) {
+		handleError(c, domainerrors.ErrForbidden)
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	pr, err := h.prService.MergePR(ctx, req.PullRequestID, req.Force)
+	if err != nil {
+		handleError(c, err)
 		return
 	}
 
-	pr, err := h.prService.MergePR(req.PullRequestID)
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+func (h *PRHandler) MarkReady(c *gin.Context) {
+	h.changeStatus(c, h.prService.MarkReady)
+}
+
+func (h *PRHandler) ClosePR(c *gin.Context) {
+	h.changeStatus(c, h.prService.ClosePR)
+}
+
+func (h *PRHandler) ReopenPR(c *gin.Context) {
+	h.changeStatus(c, h.prService.ReopenPR)
+}
+
+func (h *PRHandler) changeStatus(c *gin.Context, change func(ctx context.Context, prID string) (*models.PullRequest, error)) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingError(c, &req, err)
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	pr, err := change(ctx, req.PullRequestID)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -57,13 +107,17 @@ func (h *PRHandler) ReassignReviewer(c *gin.Context) {
 	var req struct {
 		PullRequestID string `json:"pull_request_id" binding:"required"`
 		OldUserID     string `json:"old_user_id" binding:"required"`
+		NewUserID     string `json:"new_user_id"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		errorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		bindingError(c, &req, err)
 		return
 	}
 
-	newReviewerID, pr, err := h.prService.ReassignReviewer(req.PullRequestID, req.OldUserID)
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	newReviewerID, pr, err := h.prService.ReassignReviewer(ctx, req.PullRequestID, req.OldUserID, req.NewUserID)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -74,3 +128,126 @@ func (h *PRHandler) ReassignReviewer(c *gin.Context) {
 		"replaced_by": newReviewerID,
 	})
 }
+
+func (h *PRHandler) DeclineReview(c *gin.Context) {
+	var req struct {
+		PullRequestID string                 `json:"pull_request_id" binding:"required"`
+		ReviewerID    string                 `json:"reviewer_id" binding:"required"`
+		Category      models.DeclineCategory `json:"category" binding:"omitempty,oneof=OVERLOADED OUT_OF_CONTEXT CONFLICT_OF_INTEREST OTHER"`
+		Reason        string                 `json:"reason" binding:"required,max=500"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingError(c, &req, err)
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	newReviewerID, pr, err := h.prService.DeclineReview(ctx, req.PullRequestID, req.ReviewerID, req.Category, req.Reason)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pr":          pr,
+		"replaced_by": newReviewerID,
+	})
+}
+
+func (h *PRHandler) AddReviewer(c *gin.Context) {
+	h.changeReviewer(c, h.prService.AddReviewer)
+}
+
+func (h *PRHandler) RemoveReviewer(c *gin.Context) {
+	h.changeReviewer(c, h.prService.RemoveReviewer)
+}
+
+func (h *PRHandler) changeReviewer(c *gin.Context, change func(ctx context.Context, prID, reviewerID string) (*models.PullRequest, error)) {
+	var req struct {
+		PullRequestID string `json:"pull_request_id" binding:"required"`
+		UserID        string `json:"user_id" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingError(c, &req, err)
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	pr, err := change(ctx, req.PullRequestID, req.UserID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+func (h *PRHandler) GetPR(c *gin.Context) {
+	prID := c.Query("pull_request_id")
+	if prID == "" {
+		invalidParam(c, "pull_request_id", "required", "pull_request_id is required")
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	pr, err := h.prService.GetPRDetails(ctx, prID)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+func (h *PRHandler) SubmitReview(c *gin.Context) {
+	var req struct {
+		PullRequestID string             `json:"pull_request_id" binding:"required"`
+		ReviewerID    string             `json:"reviewer_id" binding:"required"`
+		State         models.ReviewState `json:"state" binding:"required,oneof=APPROVED CHANGES_REQUESTED COMMENTED"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingError(c, &req, err)
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	pr, err := h.prService.SubmitReview(ctx, req.PullRequestID, req.ReviewerID, req.State)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pr": pr})
+}
+
+func (h *PRHandler) isAdmin(c *gin.Context) bool {
+	token := c.GetHeader("X-Admin-Token")
+	return h.adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) == 1
+}
+
+func (h *PRHandler) ListPRs(c *gin.Context) {
+	var filter models.PRListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		bindingError(c, &filter, err)
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	page, err := h.prService.ListPRs(ctx, &filter)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
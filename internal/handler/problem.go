@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	domainerrors "github.com/avito/pr-reviewer-service/internal/domain/errors"
+	"github.com/avito/pr-reviewer-service/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+const problemContentType = "application/problem+json"
+
+func wantsProblem(c *gin.Context) bool {
+	for _, part := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != problemContentType {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if weight, parseErr := strconv.ParseFloat(q, 64); parseErr != nil || weight == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func writeProblem(c *gin.Context, status int, code, detail string, details []string, violations []models.FieldViolation) {
+	c.Header("Content-Type", problemContentType)
+	c.JSON(status, models.ProblemDetails{
+		Type:     "urn:pr-reviewer:problem:" + strings.ReplaceAll(strings.ToLower(code), "_", "-"),
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.GetString("request_id"),
+		Code:     code,
+		Details:  details,
+		Errors:   violations,
+	})
+}
+
+func bindingError(c *gin.Context, req interface{}, err error) {
+	if !wantsProblem(c) {
+		errorResponse(c, http.StatusBadRequest, domainerrors.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	violations := fieldViolations(req, err)
+	detail := "request validation failed"
+	if len(violations) == 0 {
+		detail = "request could not be parsed"
+	}
+	writeProblem(c, http.StatusBadRequest, domainerrors.CodeInvalidRequest, detail, nil, violations)
+}
+
+func invalidParam(c *gin.Context, field, rule, message string) {
+	if !wantsProblem(c) {
+		errorResponse(c, http.StatusBadRequest, domainerrors.CodeInvalidRequest, message)
+		return
+	}
+
+	writeProblem(c, http.StatusBadRequest, domainerrors.CodeInvalidRequest, "request validation failed", nil, []models.FieldViolation{
+		{Field: field, Rule: rule, Message: message},
+	})
+}
+
+func fieldViolations(req interface{}, err error) []models.FieldViolation {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		violations := make([]models.FieldViolation, 0, len(validationErrs))
+		for _, fieldErr := range validationErrs {
+			field := fieldPath(reflect.TypeOf(req), fieldErr.StructNamespace())
+			violations = append(violations, models.FieldViolation{
+				Field:   field,
+				Rule:    fieldErr.Tag(),
+				Message: violationMessage(field, fieldErr),
+			})
+		}
+		return violations
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []models.FieldViolation{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: typeErr.Field + " must be " + typeErr.Type.String(),
+		}}
+	}
+
+	return nil
+}
+
+func fieldPath(t reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")
+	if len(segments) > 1 {
+		segments = segments[1:]
+	}
+
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		name, index, _ := strings.Cut(segment, "[")
+		for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice) {
+			t = t.Elem()
+		}
+
+		if t != nil && t.Kind() == reflect.Struct {
+			if field, ok := t.FieldByName(name); ok {
+				name = tagName(field)
+				t = field.Type
+			} else {
+				t = nil
+			}
+		}
+
+		if index != "" {
+			name += "[" + index
+		}
+		path = append(path, name)
+	}
+	return strings.Join(path, ".")
+}
+
+func tagName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(key), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func violationMessage(field string, fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return field + " is required"
+	case "oneof":
+		return field + " must be one of: " + strings.ReplaceAll(fieldErr.Param(), " ", ", ")
+	case "min":
+		return field + " must be at least " + fieldErr.Param()
+	case "max":
+		return field + " must be at most " + fieldErr.Param()
+	default:
+		return field + " failed " + fieldErr.Tag() + " validation"
+	}
+}
@@ -1,27 +1,47 @@
 package handler
 
 import (
+	"context"
+
 	"github.com/avito/pr-reviewer-service/internal/models"
 )
 
 type PRServiceInterface interface {
-	CreatePR(prID, prName, authorID string) (*models.PullRequest, error)
-	MergePR(prID string) (*models.PullRequest, error)
-	ReassignReviewer(prID, oldReviewerID string) (string, *models.PullRequest, error)
-	GetPRsByReviewer(reviewerID string) ([]models.PullRequestShort, error)
+	CreatePR(ctx context.Context, prID, prName, authorID string, draft bool) (*models.PullRequest, error)
+	GetPR(ctx context.Context, prID string) (*models.PullRequest, error)
+	GetPRDetails(ctx context.Context, prID string) (*models.PullRequest, error)
+	ListPRs(ctx context.Context, filter *models.PRListFilter) (*models.PRListPage, error)
+	MergePR(ctx context.Context, prID string, force bool) (*models.PullRequest, error)
+	MarkReady(ctx context.Context, prID string) (*models.PullRequest, error)
+	ClosePR(ctx context.Context, prID string) (*models.PullRequest, error)
+	ReopenPR(ctx context.Context, prID string) (*models.PullRequest, error)
+	SubmitReview(ctx context.Context, prID, reviewerID string, state models.ReviewState) (*models.PullRequest, error)
+	AddReviewer(ctx context.Context, prID, reviewerID string) (*models.PullRequest, error)
+	RemoveReviewer(ctx context.Context, prID, reviewerID string) (*models.PullRequest, error)
+	DeclineReview(ctx context.Context, prID, reviewerID string, category models.DeclineCategory, reason string) (string, *models.PullRequest, error)
+	ReassignReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) (string, *models.PullRequest, error)
+	GetPRsByReviewer(ctx context.Context, reviewerID string, filter *models.ReviewListFilter) ([]models.PullRequestShort, string, error)
 }
 
 type TeamServiceInterface interface {
-	CreateTeam(team *models.Team) error
-	GetTeam(teamName string) (*models.Team, error)
-	BulkDeactivateTeam(teamName string) error
+	CreateTeam(ctx context.Context, team *models.Team) error
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	BulkDeactivateTeam(ctx context.Context, teamName string, dryRun bool) (*models.BulkDeactivationReport, error)
+	GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	UpdateTeamSettings(ctx context.Context, update *models.TeamSettingsUpdate) (*models.TeamSettings, error)
 }
 
 type UserServiceInterface interface {
-	SetIsActive(userID string, isActive bool) (*models.User, error)
+	SetIsActive(ctx context.Context, userID string, isActive bool, reassignReviews bool) (*models.User, *models.ReassignmentReport, error)
+	SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (*models.User, error)
 }
 
-type StatsServiceInterface interface {
-	GetStats() (*models.StatsResponse, error)
+type AbsenceServiceInterface interface {
+	CreateAbsence(ctx context.Context, absence *models.Absence) (*models.Absence, error)
+	GetAbsences(ctx context.Context, userID string) ([]models.Absence, error)
+	DeleteAbsence(ctx context.Context, absenceID int64) error
 }
 
+type StatsServiceInterface interface {
+	GetStats(ctx context.Context) (*models.StatsResponse, error)
+}
@@ -3,7 +3,6 @@ package handler
 import (
 	"net/http"
 
-	"github.com/avito/pr-reviewer-service/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -11,12 +10,15 @@ type StatsHandler struct {
 	statsService StatsServiceInterface
 }
 
-func NewStatsHandler(statsService *service.StatsService) *StatsHandler {
+func NewStatsHandler(statsService StatsServiceInterface) *StatsHandler {
 	return &StatsHandler{statsService: statsService}
 }
 
 func (h *StatsHandler) GetStats(c *gin.Context) {
-	stats, err := h.statsService.GetStats()
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	stats, err := h.statsService.GetStats(ctx)
 	if err != nil {
 		handleError(c, err)
 		return
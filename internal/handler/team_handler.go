@@ -4,7 +4,6 @@ import (
 	"net/http"
 
 	"github.com/avito/pr-reviewer-service/internal/models"
-	"github.com/avito/pr-reviewer-service/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,18 +11,21 @@ type TeamHandler struct {
 	teamService TeamServiceInterface
 }
 
-func NewTeamHandler(teamService *service.TeamService) *TeamHandler {
+func NewTeamHandler(teamService TeamServiceInterface) *TeamHandler {
 	return &TeamHandler{teamService: teamService}
 }
 
 func (h *TeamHandler) AddTeam(c *gin.Context) {
 	var team models.Team
 	if err := c.ShouldBindJSON(&team); err != nil {
-		errorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		bindingError(c, &team, err)
 		return
 	}
 
-	if err := h.teamService.CreateTeam(&team); err != nil {
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	if err := h.teamService.CreateTeam(ctx, &team); err != nil {
 		handleError(c, err)
 		return
 	}
@@ -34,11 +36,14 @@ func (h *TeamHandler) AddTeam(c *gin.Context) {
 func (h *TeamHandler) GetTeam(c *gin.Context) {
 	teamName := c.Query("team_name")
 	if teamName == "" {
-		errorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "team_name is required")
+		invalidParam(c, "team_name", "required", "team_name is required")
 		return
 	}
 
-	team, err := h.teamService.GetTeam(teamName)
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	team, err := h.teamService.GetTeam(ctx, teamName)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -50,16 +55,67 @@ func (h *TeamHandler) GetTeam(c *gin.Context) {
 func (h *TeamHandler) BulkDeactivateTeam(c *gin.Context) {
 	var req struct {
 		TeamName string `json:"team_name" binding:"required"`
+		DryRun   bool   `json:"dry_run"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		errorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		bindingError(c, &req, err)
+		return
+	}
+	dryRun := req.DryRun || c.Query("dry_run") == "true"
+
+	ctx, cancel := operationContext(c, bulkOperationTimeout)
+	defer cancel()
+
+	report, err := h.teamService.BulkDeactivateTeam(ctx, req.TeamName, dryRun)
+	if err != nil {
+		handleError(c, err)
 		return
 	}
 
-	if err := h.teamService.BulkDeactivateTeam(req.TeamName); err != nil {
+	message := "team members deactivated successfully"
+	if dryRun {
+		message = "dry run: no changes applied"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"report":  report,
+	})
+}
+
+func (h *TeamHandler) GetTeamSettings(c *gin.Context) {
+	teamName := c.Query("team_name")
+	if teamName == "" {
+		invalidParam(c, "team_name", "required", "team_name is required")
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	settings, err := h.teamService.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+func (h *TeamHandler) UpdateTeamSettings(c *gin.Context) {
+	var update models.TeamSettingsUpdate
+	if err := c.ShouldBindJSON(&update); err != nil {
+		bindingError(c, &update, err)
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	settings, err := h.teamService.UpdateTeamSettings(ctx, &update)
+	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "team members deactivated successfully"})
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
 }
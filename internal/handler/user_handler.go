@@ -3,7 +3,7 @@ package handler
 import (
 	"net/http"
 
-	"github.com/avito/pr-reviewer-service/internal/service"
+	"github.com/avito/pr-reviewer-service/internal/models"
 	"github.com/gin-gonic/gin"
 )
 
@@ -12,21 +12,51 @@ type UserHandler struct {
 	prService   PRServiceInterface
 }
 
-func NewUserHandler(userService *service.UserService, prService *service.PRService) *UserHandler {
+func NewUserHandler(userService UserServiceInterface, prService PRServiceInterface) *UserHandler {
 	return &UserHandler{userService: userService, prService: prService}
 }
 
 func (h *UserHandler) SetIsActive(c *gin.Context) {
 	var req struct {
-		UserID   string `json:"user_id" binding:"required"`
-		IsActive bool   `json:"is_active"`
+		UserID          string `json:"user_id" binding:"required"`
+		IsActive        bool   `json:"is_active"`
+		ReassignReviews bool   `json:"reassign_reviews"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		errorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+		bindingError(c, &req, err)
 		return
 	}
 
-	user, err := h.userService.SetIsActive(req.UserID, req.IsActive)
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	user, report, err := h.userService.SetIsActive(ctx, req.UserID, req.IsActive, req.ReassignReviews)
+	if err != nil {
+		handleError(c, err)
+		return
+	}
+
+	response := gin.H{"user": user}
+	if report != nil {
+		response["reassignment"] = report
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (h *UserHandler) SetMaxOpenReviews(c *gin.Context) {
+	var req struct {
+		UserID         string `json:"user_id" binding:"required"`
+		MaxOpenReviews *int   `json:"max_open_reviews" binding:"omitempty,min=0"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		bindingError(c, &req, err)
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	user, err := h.userService.SetMaxOpenReviews(ctx, req.UserID, req.MaxOpenReviews)
 	if err != nil {
 		handleError(c, err)
 		return
@@ -38,18 +68,31 @@ func (h *UserHandler) SetIsActive(c *gin.Context) {
 func (h *UserHandler) GetReview(c *gin.Context) {
 	userID := c.Query("user_id")
 	if userID == "" {
-		errorResponse(c, http.StatusBadRequest, "INVALID_REQUEST", "user_id is required")
+		invalidParam(c, "user_id", "required", "user_id is required")
 		return
 	}
 
-	prs, err := h.prService.GetPRsByReviewer(userID)
+	var filter models.ReviewListFilter
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		bindingError(c, &filter, err)
+		return
+	}
+
+	ctx, cancel := operationContext(c, operationTimeout)
+	defer cancel()
+
+	prs, nextCursor, err := h.prService.GetPRsByReviewer(ctx, userID, &filter)
 	if err != nil {
 		handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"user_id":       userID,
 		"pull_requests": prs,
-	})
+	}
+	if nextCursor != "" {
+		response["next_cursor"] = nextCursor
+	}
+	c.JSON(http.StatusOK, response)
 }
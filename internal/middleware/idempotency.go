@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	domainerrors "github.com/avito/pr-reviewer-service/internal/domain/errors"
+	"github.com/avito/pr-reviewer-service/internal/handler"
+	"github.com/avito/pr-reviewer-service/internal/models"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+	idempotencyStoreTimeout  = 5 * time.Second
+)
+
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (*models.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, key string) error
+}
+
+type capturingWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *capturingWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *capturingWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+func Idempotency(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			handler.AbortWithError(c, domainerrors.ErrInvalidIdempotencyKey)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			handler.AbortWithError(c, domainerrors.New(domainerrors.CodeInvalidRequest, http.StatusBadRequest, "failed to read request body"))
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		hash := sha256.New()
+		hash.Write([]byte(c.Request.Method + " " + c.Request.URL.Path + "\n"))
+		hash.Write(body)
+		requestHash := hex.EncodeToString(hash.Sum(nil))
+
+		record, reserved, err := store.Reserve(c.Request.Context(), key, requestHash, ttl)
+		if err != nil {
+			handler.AbortWithError(c, fmt.Errorf("failed to reserve idempotency key: %w", err))
+			return
+		}
+
+		if !reserved {
+			switch {
+			case record.RequestHash != requestHash:
+				handler.AbortWithError(c, domainerrors.ErrIdempotencyKeyReused)
+			case record.StatusCode == 0:
+				handler.AbortWithError(c, domainerrors.ErrIdempotencyInProgress)
+			default:
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(record.StatusCode, record.ContentType, record.ResponseBody)
+				c.Abort()
+			}
+			return
+		}
+
+		writer := &capturingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+
+		completed := false
+		defer func() {
+			if completed {
+				return
+			}
+			ctx, cancel := storeContext(c)
+			defer cancel()
+			if releaseErr := store.Release(ctx, key); releaseErr != nil {
+				log.Error().
+					Err(releaseErr).
+					Str("request_id", c.GetString("request_id")).
+					Msg("Failed to release idempotency key")
+			}
+		}()
+
+		c.Next()
+
+		status := writer.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+		ctx, cancel := storeContext(c)
+		defer cancel()
+		if err = store.Complete(ctx, key, status, writer.Header().Get("Content-Type"), writer.body.Bytes()); err != nil {
+			log.Error().
+				Err(err).
+				Str("request_id", c.GetString("request_id")).
+				Msg("Failed to store idempotent response")
+			return
+		}
+		completed = true
+	}
+}
+
+func storeContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(c.Request.Context()), idempotencyStoreTimeout)
+}
@@ -9,32 +9,115 @@ type TeamMember struct {
 }
 
 type Team struct {
-	TeamName string      `json:"team_name" db:"team_name"`
+	TeamName string       `json:"team_name" db:"team_name"`
 	Members  []TeamMember `json:"members"`
 }
 
+type TeamSettings struct {
+	TeamName                string `json:"team_name" db:"team_name"`
+	ReviewerCount           int    `json:"reviewer_count" db:"reviewer_count"`
+	SelectionStrategy       string `json:"selection_strategy" db:"selection_strategy"`
+	MinApprovals            int    `json:"min_approvals" db:"min_approvals"`
+	BlockOnChangesRequested bool   `json:"block_on_changes_requested" db:"block_on_changes_requested"`
+	RequireAllApproved      bool   `json:"require_all_approved" db:"require_all_approved"`
+	SLAHours                int    `json:"sla_hours" db:"sla_hours"`
+}
+
+type TeamSettingsUpdate struct {
+	TeamName                string  `json:"team_name" binding:"required"`
+	ReviewerCount           *int    `json:"reviewer_count" binding:"omitempty,min=0,max=10"`
+	SelectionStrategy       *string `json:"selection_strategy"`
+	MinApprovals            *int    `json:"min_approvals" binding:"omitempty,min=0"`
+	BlockOnChangesRequested *bool   `json:"block_on_changes_requested"`
+	RequireAllApproved      *bool   `json:"require_all_approved"`
+	SLAHours                *int    `json:"sla_hours" binding:"omitempty,min=0"`
+}
+
 type User struct {
-	UserID   string `json:"user_id" db:"user_id"`
-	Username string `json:"username" db:"username"`
-	TeamName string `json:"team_name" db:"team_name"`
-	IsActive bool   `json:"is_active" db:"is_active"`
+	UserID         string `json:"user_id" db:"user_id"`
+	Username       string `json:"username" db:"username"`
+	TeamName       string `json:"team_name" db:"team_name"`
+	IsActive       bool   `json:"is_active" db:"is_active"`
+	MaxOpenReviews *int   `json:"max_open_reviews,omitempty" db:"max_open_reviews"`
+}
+
+type Absence struct {
+	AbsenceID int64     `json:"absence_id" db:"absence_id"`
+	UserID    string    `json:"user_id" db:"user_id"`
+	StartsAt  time.Time `json:"starts_at" db:"starts_at"`
+	EndsAt    time.Time `json:"ends_at" db:"ends_at"`
+	Reason    string    `json:"reason" db:"reason"`
 }
 
 type PullRequestStatus string
 
 const (
-	StatusOpen   PullRequestStatus = "OPEN"
-	StatusMerged PullRequestStatus = "MERGED"
+	StatusDraft    PullRequestStatus = "DRAFT"
+	StatusOpen     PullRequestStatus = "OPEN"
+	StatusClosed   PullRequestStatus = "CLOSED"
+	StatusReopened PullRequestStatus = "REOPENED"
+	StatusMerged   PullRequestStatus = "MERGED"
+)
+
+func (s PullRequestStatus) IsOpen() bool {
+	return s == StatusOpen || s == StatusReopened
+}
+
+type ReviewState string
+
+const (
+	ReviewPending          ReviewState = "PENDING"
+	ReviewApproved         ReviewState = "APPROVED"
+	ReviewChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewCommented        ReviewState = "COMMENTED"
 )
 
+type Reviewer struct {
+	ReviewerID string      `json:"reviewer_id"`
+	State      ReviewState `json:"state"`
+	AssignedAt *time.Time  `json:"assigned_at,omitempty"`
+	ReviewedAt *time.Time  `json:"reviewed_at,omitempty"`
+}
+
+type DeclineCategory string
+
+const (
+	DeclineOverloaded         DeclineCategory = "OVERLOADED"
+	DeclineOutOfContext       DeclineCategory = "OUT_OF_CONTEXT"
+	DeclineConflictOfInterest DeclineCategory = "CONFLICT_OF_INTEREST"
+	DeclineOther              DeclineCategory = "OTHER"
+)
+
+type ReviewerChangeReason string
+
+const (
+	ChangeReasonReassign     ReviewerChangeReason = "REASSIGN"
+	ChangeReasonDeactivation ReviewerChangeReason = "DEACTIVATION"
+	ChangeReasonAdd          ReviewerChangeReason = "ADD"
+	ChangeReasonRemove       ReviewerChangeReason = "REMOVE"
+	ChangeReasonDecline      ReviewerChangeReason = "DECLINE"
+)
+
+type ReviewerChange struct {
+	OldReviewerID string               `json:"old_reviewer_id,omitempty"`
+	NewReviewerID string               `json:"new_reviewer_id,omitempty"`
+	Reason        ReviewerChangeReason `json:"reason"`
+	ChangedAt     time.Time            `json:"changed_at"`
+}
+
 type PullRequest struct {
-	PullRequestID    string             `json:"pull_request_id" db:"pull_request_id"`
-	PullRequestName  string             `json:"pull_request_name" db:"pull_request_name"`
-	AuthorID         string             `json:"author_id" db:"author_id"`
-	Status           PullRequestStatus  `json:"status" db:"status"`
-	AssignedReviewers []string          `json:"assigned_reviewers"`
-	CreatedAt        *time.Time         `json:"createdAt,omitempty" db:"created_at"`
-	MergedAt         *time.Time         `json:"mergedAt,omitempty" db:"merged_at"`
+	PullRequestID       string            `json:"pull_request_id" db:"pull_request_id"`
+	PullRequestName     string            `json:"pull_request_name" db:"pull_request_name"`
+	AuthorID            string            `json:"author_id" db:"author_id"`
+	AuthorTeam          string            `json:"author_team,omitempty" db:"team_name"`
+	Status              PullRequestStatus `json:"status" db:"status"`
+	AssignedReviewers   []string          `json:"assigned_reviewers"`
+	Reviewers           []Reviewer        `json:"reviewers"`
+	CreatedAt           *time.Time        `json:"createdAt,omitempty" db:"created_at"`
+	MergedAt            *time.Time        `json:"mergedAt,omitempty" db:"merged_at"`
+	ClosedAt            *time.Time        `json:"closedAt,omitempty" db:"closed_at"`
+	DeclinedReviewers   []string          `json:"declined_reviewers,omitempty"`
+	ReassignmentHistory []ReviewerChange  `json:"reassignment_history,omitempty"`
 }
 
 type PullRequestShort struct {
@@ -42,28 +125,126 @@ type PullRequestShort struct {
 	PullRequestName string            `json:"pull_request_name"`
 	AuthorID        string            `json:"author_id"`
 	Status          PullRequestStatus `json:"status"`
+	CreatedAt       *time.Time        `json:"createdAt,omitempty"`
+	AssignedAt      *time.Time        `json:"assigned_at,omitempty"`
+}
+
+type ReviewListFilter struct {
+	Status   string              `form:"status"`
+	Statuses []PullRequestStatus `form:"-"`
+	Limit    int                 `form:"limit" binding:"omitempty,min=1,max=200"`
+	Cursor   string              `form:"cursor"`
+}
+
+const (
+	SortByCreatedAt = "created_at"
+	SortByName      = "name"
+	SortAsc         = "asc"
+	SortDesc        = "desc"
+)
+
+type PRListFilter struct {
+	Status       string              `form:"status"`
+	Statuses     []PullRequestStatus `form:"-"`
+	AuthorID     string              `form:"author_id"`
+	ReviewerID   string              `form:"reviewer_id"`
+	TeamName     string              `form:"team_name"`
+	CreatedFrom  *time.Time          `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo    *time.Time          `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	MergedFrom   *time.Time          `form:"merged_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	MergedTo     *time.Time          `form:"merged_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	NameContains string              `form:"q"`
+	Sort         string              `form:"sort" binding:"omitempty,oneof=created_at name"`
+	Order        string              `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit        int                 `form:"limit" binding:"omitempty,min=1,max=200"`
+	Cursor       string              `form:"cursor"`
+}
+
+type PRListPage struct {
+	PullRequests []PullRequest `json:"pull_requests"`
+	NextCursor   string        `json:"next_cursor,omitempty"`
+}
+
+type ReviewerReassignment struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldReviewerID string `json:"old_reviewer_id"`
+	NewReviewerID string `json:"new_reviewer_id,omitempty"`
+}
+
+type ReassignmentReport struct {
+	Reassigned  []ReviewerReassignment `json:"reassigned"`
+	NoCandidate []ReviewerReassignment `json:"no_candidate"`
+}
+
+type PRReassignmentReport struct {
+	PullRequestID string                 `json:"pull_request_id"`
+	AuthorID      string                 `json:"author_id"`
+	Reassigned    []ReviewerReassignment `json:"reassigned"`
+	NoCandidate   []ReviewerReassignment `json:"no_candidate"`
+}
+
+type BulkDeactivationReport struct {
+	TeamName         string                 `json:"team_name"`
+	DryRun           bool                   `json:"dry_run"`
+	DeactivatedUsers []string               `json:"deactivated_users"`
+	PullRequests     []PRReassignmentReport `json:"pull_requests"`
+}
+
+type IdempotencyRecord struct {
+	Key          string
+	RequestHash  string
+	StatusCode   int
+	ContentType  string
+	ResponseBody []byte
 }
 
 type ErrorResponse struct {
 	Error struct {
-		Code    string `json:"code"`
-		Message string `json:"message"`
+		Code    string   `json:"code"`
+		Message string   `json:"message"`
+		Details []string `json:"details,omitempty"`
 	} `json:"error"`
 }
 
+type ProblemDetails struct {
+	Type     string           `json:"type"`
+	Title    string           `json:"title"`
+	Status   int              `json:"status"`
+	Detail   string           `json:"detail,omitempty"`
+	Instance string           `json:"instance,omitempty"`
+	Code     string           `json:"code"`
+	Details  []string         `json:"details,omitempty"`
+	Errors   []FieldViolation `json:"errors,omitempty"`
+}
+
+type FieldViolation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
+
 type StatsResponse struct {
 	UserStats []UserStat `json:"user_stats"`
 	PRStats   PRStat     `json:"pr_stats"`
 }
 
 type UserStat struct {
-	UserID          string `json:"user_id"`
-	Username        string `json:"username"`
-	AssignedCount   int    `json:"assigned_count"`
+	UserID            string                `json:"user_id"`
+	Username          string                `json:"username"`
+	AssignedCount     int                   `json:"assigned_count"`
+	DeclinedCount     int                   `json:"declined_count"`
+	DeclineCategories []DeclineCategoryStat `json:"decline_categories,omitempty"`
+}
+
+type DeclineCategoryStat struct {
+	Category DeclineCategory `json:"category"`
+	Count    int             `json:"count"`
 }
 
 type PRStat struct {
-	TotalPRs        int `json:"total_prs"`
-	OpenPRs         int `json:"open_prs"`
-	MergedPRs       int `json:"merged_prs"`
+	TotalPRs  int `json:"total_prs"`
+	DraftPRs  int `json:"draft_prs"`
+	OpenPRs   int `json:"open_prs"`
+	ClosedPRs int `json:"closed_prs"`
+	MergedPRs int `json:"merged_prs"`
 }
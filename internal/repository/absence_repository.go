@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+type AbsenceRepository struct {
+	db *sql.DB
+}
+
+func NewAbsenceRepository(db *sql.DB) *AbsenceRepository {
+	return &AbsenceRepository{db: db}
+}
+
+func (r *AbsenceRepository) CreateAbsence(ctx context.Context, absence *models.Absence) error {
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		INSERT INTO user_absences (user_id, starts_at, ends_at, reason)
+		VALUES ($1, $2, $3, $4)
+		RETURNING absence_id
+	`, absence.UserID, absence.StartsAt, absence.EndsAt, absence.Reason).Scan(&absence.AbsenceID)
+	if err != nil {
+		return fmt.Errorf("failed to create absence: %w", err)
+	}
+	return nil
+}
+
+func (r *AbsenceRepository) GetAbsencesByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT absence_id, user_id, starts_at, ends_at, reason
+		FROM user_absences
+		WHERE user_id = $1
+		ORDER BY starts_at
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absences: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	absences := []models.Absence{}
+	for rows.Next() {
+		var absence models.Absence
+		if err := rows.Scan(&absence.AbsenceID, &absence.UserID, &absence.StartsAt, &absence.EndsAt, &absence.Reason); err != nil {
+			return nil, fmt.Errorf("failed to scan absence: %w", err)
+		}
+		absences = append(absences, absence)
+	}
+
+	return absences, rows.Err()
+}
+
+func (r *AbsenceRepository) DeleteAbsence(ctx context.Context, absenceID int64) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM user_absences WHERE absence_id = $1", absenceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete absence: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to delete absence: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
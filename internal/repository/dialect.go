@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+type dialect int
+
+const (
+	dialectPostgres dialect = iota
+	dialectSQLite
+)
+
+const sqliteNow = "strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')"
+
+var positionalParam = regexp.MustCompile(`\$(\d+)`)
+
+func dialectOf(db *sql.DB) dialect {
+	if _, ok := db.Driver().(*sqlite3.SQLiteDriver); ok {
+		return dialectSQLite
+	}
+	return dialectPostgres
+}
+
+func (d dialect) bind(q queryer) queryer {
+	if d == dialectSQLite {
+		return sqliteQueryer{q}
+	}
+	return q
+}
+
+func (d dialect) forUpdate() string {
+	if d == dialectSQLite {
+		return ""
+	}
+	return "FOR UPDATE"
+}
+
+func (d dialect) nowPlusSeconds(seconds string) string {
+	if d == dialectSQLite {
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d %%H:%%M:%%f+00:00', 'now', '+' || %s || ' seconds')", seconds)
+	}
+	return fmt.Sprintf("CURRENT_TIMESTAMP + %s * INTERVAL '1 second'", seconds)
+}
+
+func (d dialect) anyOf(q *prListQuery, column string, values []string) string {
+	if d == dialectSQLite {
+		placeholders := make([]string, 0, len(values))
+		for _, value := range values {
+			placeholders = append(placeholders, q.arg(value))
+		}
+		return fmt.Sprintf("%s IN (%s)", column, strings.Join(placeholders, ", "))
+	}
+	return fmt.Sprintf("%s = ANY(%s)", column, q.arg(pq.Array(values)))
+}
+
+func (d dialect) containsFold(q *prListQuery, column, pattern string) string {
+	if d == dialectSQLite {
+		return fmt.Sprintf(`%s LIKE '%%' || %s || '%%' ESCAPE '\'`, column, q.arg(pattern))
+	}
+	return fmt.Sprintf(`%s ILIKE '%%' || %s || '%%'`, column, q.arg(pattern))
+}
+
+type sqliteQueryer struct {
+	queryer
+}
+
+func (q sqliteQueryer) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return q.queryer.ExecContext(ctx, rebindSQLite(query), sqliteArgs(args)...)
+}
+
+func (q sqliteQueryer) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return q.queryer.QueryContext(ctx, rebindSQLite(query), sqliteArgs(args)...)
+}
+
+func (q sqliteQueryer) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return q.queryer.QueryRowContext(ctx, rebindSQLite(query), sqliteArgs(args)...)
+}
+
+func rebindSQLite(query string) string {
+	query = positionalParam.ReplaceAllString(query, "?$1")
+	return strings.ReplaceAll(query, "CURRENT_TIMESTAMP", sqliteNow)
+}
+
+func sqliteArgs(args []interface{}) []interface{} {
+	converted := make([]interface{}, len(args))
+	for i, arg := range args {
+		if t, ok := arg.(time.Time); ok {
+			arg = t.UTC()
+		}
+		converted[i] = arg
+	}
+	return converted
+}
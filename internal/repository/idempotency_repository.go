@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+type IdempotencyRepository struct {
+	db      *sql.DB
+	dialect dialect
+}
+
+func NewIdempotencyRepository(db *sql.DB) *IdempotencyRepository {
+	return &IdempotencyRepository{db: db, dialect: dialectOf(db)}
+}
+
+func (r *IdempotencyRepository) Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (*models.IdempotencyRecord, bool, error) {
+	_, tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE idempotency_key = $1 AND expires_at <= CURRENT_TIMESTAMP
+	`, key)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to expire idempotency key: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		INSERT INTO idempotency_keys (idempotency_key, request_hash, created_at, expires_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP, %s)
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`, r.dialect.nowPlusSeconds("$3")), key, requestHash, int64(ttl/time.Second))
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	var record models.IdempotencyRecord
+	var statusCode sql.NullInt64
+	var contentType sql.NullString
+	err = tx.QueryRowContext(ctx, `
+		SELECT idempotency_key, request_hash, status_code, content_type, response_body
+		FROM idempotency_keys
+		WHERE idempotency_key = $1
+	`, key).Scan(&record.Key, &record.RequestHash, &statusCode, &contentType, &record.ResponseBody)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+	record.StatusCode = int(statusCode.Int64)
+	record.ContentType = contentType.String
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, err
+	}
+
+	return &record, affected == 1, nil
+}
+
+func (r *IdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE idempotency_keys
+		SET status_code = $1, content_type = $2, response_body = $3
+		WHERE idempotency_key = $4
+	`, statusCode, contentType, body, key)
+	if err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE idempotency_key = $1 AND status_code IS NULL
+	`, key)
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+func (r *IdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= CURRENT_TIMESTAMP`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+	return result.RowsAffected()
+}
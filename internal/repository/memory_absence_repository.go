@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+type MemoryAbsenceRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryAbsenceRepository(store *MemoryStore) *MemoryAbsenceRepository {
+	return &MemoryAbsenceRepository{store: store}
+}
+
+func (r *MemoryAbsenceRepository) CreateAbsence(ctx context.Context, absence *models.Absence) error {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := r.store.data.users[absence.UserID]; !ok {
+		return fmt.Errorf("failed to create absence: user %q does not exist", absence.UserID)
+	}
+
+	r.store.data.nextAbsenceID++
+	absence.AbsenceID = r.store.data.nextAbsenceID
+	r.store.data.absences[absence.AbsenceID] = *absence
+	return nil
+}
+
+func (r *MemoryAbsenceRepository) GetAbsencesByUser(ctx context.Context, userID string) ([]models.Absence, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	absences := []models.Absence{}
+	for _, absence := range r.store.data.absences {
+		if absence.UserID == userID {
+			absences = append(absences, absence)
+		}
+	}
+	slices.SortFunc(absences, func(a, b models.Absence) int {
+		if c := a.StartsAt.Compare(b.StartsAt); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.AbsenceID, b.AbsenceID)
+	})
+	return absences, nil
+}
+
+func (r *MemoryAbsenceRepository) DeleteAbsence(ctx context.Context, absenceID int64) error {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, ok := r.store.data.absences[absenceID]; !ok {
+		return sql.ErrNoRows
+	}
+	delete(r.store.data.absences, absenceID)
+	return nil
+}
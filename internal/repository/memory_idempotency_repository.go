@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+type MemoryIdempotencyRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryIdempotencyRepository(store *MemoryStore) *MemoryIdempotencyRepository {
+	return &MemoryIdempotencyRepository{store: store}
+}
+
+func (r *MemoryIdempotencyRepository) Reserve(ctx context.Context, key, requestHash string, ttl time.Duration) (*models.IdempotencyRecord, bool, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	defer unlock()
+
+	now := memoryNow()
+	if stored, ok := r.store.data.idempotency[key]; ok && stored.expiresAt.After(now) {
+		record := stored.record
+		record.ResponseBody = slices.Clone(record.ResponseBody)
+		return &record, false, nil
+	}
+
+	record := models.IdempotencyRecord{Key: key, RequestHash: requestHash}
+	r.store.data.idempotency[key] = memoryIdempotencyRecord{record: record, expiresAt: now.Add(ttl)}
+	return &record, true, nil
+}
+
+func (r *MemoryIdempotencyRepository) Complete(ctx context.Context, key string, statusCode int, contentType string, body []byte) error {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	stored, ok := r.store.data.idempotency[key]
+	if !ok {
+		return nil
+	}
+	stored.record.StatusCode = statusCode
+	stored.record.ContentType = contentType
+	stored.record.ResponseBody = slices.Clone(body)
+	r.store.data.idempotency[key] = stored
+	return nil
+}
+
+func (r *MemoryIdempotencyRepository) Release(ctx context.Context, key string) error {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if stored, ok := r.store.data.idempotency[key]; ok && stored.record.StatusCode == 0 {
+		delete(r.store.data.idempotency, key)
+	}
+	return nil
+}
+
+func (r *MemoryIdempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer unlock()
+
+	now := memoryNow()
+	var deleted int64
+	for key, stored := range r.store.data.idempotency {
+		if !stored.expiresAt.After(now) {
+			delete(r.store.data.idempotency, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,654 @@
+package repository
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+type MemoryPullRequestRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryPullRequestRepository(store *MemoryStore) *MemoryPullRequestRepository {
+	return &MemoryPullRequestRepository{store: store}
+}
+
+func (r *MemoryPullRequestRepository) CreatePR(ctx context.Context, pr *models.PullRequest, selectReviewers func(ctx context.Context) ([]string, error)) error {
+	ctx, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, exists := r.store.data.prs[pr.PullRequestID]; exists {
+		return ErrPRAlreadyExists
+	}
+	if _, ok := r.store.data.users[pr.AuthorID]; !ok {
+		return fmt.Errorf("failed to create PR: author %q does not exist", pr.AuthorID)
+	}
+
+	if selectReviewers != nil {
+		pr.AssignedReviewers, err = selectReviewers(ctx)
+		if err != nil {
+			return err
+		}
+	}
+
+	now := memoryNow()
+	record := &memoryPR{pr: models.PullRequest{
+		PullRequestID:   pr.PullRequestID,
+		PullRequestName: pr.PullRequestName,
+		AuthorID:        pr.AuthorID,
+		Status:          pr.Status,
+		CreatedAt:       timePtr(now),
+	}}
+	for _, reviewerID := range pr.AssignedReviewers {
+		if err = r.store.addReviewer(record, reviewerID, now); err != nil {
+			return fmt.Errorf("failed to assign reviewer: %w", err)
+		}
+	}
+
+	r.store.data.prs[pr.PullRequestID] = record
+	return nil
+}
+
+func (r *MemoryPullRequestRepository) GetPR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.prs[prID]
+	if !ok {
+		return nil, fmt.Errorf("failed to get PR: %w", sql.ErrNoRows)
+	}
+	return r.store.pullRequest(record), nil
+}
+
+func (r *MemoryPullRequestRepository) GetPRForUpdate(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return r.GetPR(ctx, prID)
+}
+
+func (r *MemoryPullRequestRepository) MergePR(ctx context.Context, prID string, check func(ctx context.Context, pr *models.PullRequest) error) (*models.PullRequest, error) {
+	ctx, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.prs[prID]
+	if !ok {
+		return nil, fmt.Errorf("failed to lock PR: %w", sql.ErrNoRows)
+	}
+	pr := r.store.pullRequest(record)
+	if pr.Status == models.StatusMerged {
+		return pr, nil
+	}
+
+	if err = check(ctx, pr); err != nil {
+		return nil, err
+	}
+
+	record.pr.Status = models.StatusMerged
+	record.pr.MergedAt = timePtr(memoryNow())
+	return r.store.pullRequest(record), nil
+}
+
+func (r *MemoryPullRequestRepository) TransitionPR(ctx context.Context, prID string, from, to models.PullRequestStatus, reviewerIDs []string) error {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.prs[prID]
+	if !ok || record.pr.Status != from {
+		return sql.ErrNoRows
+	}
+
+	now := memoryNow()
+	staged := record.clone()
+	staged.pr.Status = to
+	staged.pr.ClosedAt = nil
+	if to == models.StatusClosed {
+		staged.pr.ClosedAt = timePtr(now)
+	}
+	for _, reviewerID := range reviewerIDs {
+		if err = r.store.addReviewer(staged, reviewerID, now); err != nil {
+			return fmt.Errorf("failed to assign reviewer: %w", err)
+		}
+	}
+
+	r.store.data.prs[prID] = staged
+	return nil
+}
+
+func (r *MemoryPullRequestRepository) ReassignReviewer(ctx context.Context, prID, oldReviewerID string, pick func(ctx context.Context, pr *models.PullRequest) (string, error)) (string, error) {
+	ctx, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.prs[prID]
+	if !ok {
+		return "", fmt.Errorf("failed to lock PR: %w", sql.ErrNoRows)
+	}
+
+	newReviewerID, err := pick(ctx, r.store.pullRequest(record))
+	if err != nil {
+		return "", err
+	}
+
+	staged := record.clone()
+	if err = r.store.replaceReviewer(staged, oldReviewerID, newReviewerID, models.ChangeReasonReassign, memoryNow()); err != nil {
+		return "", err
+	}
+
+	r.store.data.prs[prID] = staged
+	return newReviewerID, nil
+}
+
+func (r *MemoryPullRequestRepository) AddReviewer(ctx context.Context, prID, reviewerID string, check func(ctx context.Context, pr *models.PullRequest) error) error {
+	ctx, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.prs[prID]
+	if !ok {
+		return fmt.Errorf("failed to lock PR: %w", sql.ErrNoRows)
+	}
+	if err = check(ctx, r.store.pullRequest(record)); err != nil {
+		return err
+	}
+	if record.reviewerIndex(reviewerID) >= 0 {
+		return ErrReviewerAlreadyAssigned
+	}
+
+	now := memoryNow()
+	if err = r.store.addReviewer(record, reviewerID, now); err != nil {
+		return fmt.Errorf("failed to add reviewer: %w", err)
+	}
+	record.recordChange("", reviewerID, models.ChangeReasonAdd, now)
+	return nil
+}
+
+func (r *MemoryPullRequestRepository) RemoveReviewer(ctx context.Context, prID, reviewerID string, check func(ctx context.Context, pr *models.PullRequest) error) error {
+	ctx, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.prs[prID]
+	if !ok {
+		return fmt.Errorf("failed to lock PR: %w", sql.ErrNoRows)
+	}
+	if err = check(ctx, r.store.pullRequest(record)); err != nil {
+		return err
+	}
+	if !record.removeReviewer(reviewerID) {
+		return ErrReviewerNotAssigned
+	}
+	record.recordChange(reviewerID, "", models.ChangeReasonRemove, memoryNow())
+	return nil
+}
+
+func (r *MemoryPullRequestRepository) DeclineReview(ctx context.Context, prID, reviewerID string, category models.DeclineCategory, reason string, pick func(ctx context.Context, pr *models.PullRequest) (string, error)) (string, error) {
+	ctx, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.prs[prID]
+	if !ok {
+		return "", fmt.Errorf("failed to lock PR: %w", sql.ErrNoRows)
+	}
+
+	newReviewerID, err := pick(ctx, r.store.pullRequest(record))
+	if err != nil {
+		return "", err
+	}
+
+	now := memoryNow()
+	staged := record.clone()
+	if !staged.removeReviewer(reviewerID) {
+		return "", ErrReviewerNotAssigned
+	}
+	staged.declines = append(staged.declines, memoryDecline{reviewerID: reviewerID, category: category, reason: reason, declinedAt: now})
+	if newReviewerID != "" {
+		if err = r.store.addReviewer(staged, newReviewerID, now); err != nil {
+			return "", fmt.Errorf("failed to add new reviewer: %w", err)
+		}
+	}
+	staged.recordChange(reviewerID, newReviewerID, models.ChangeReasonDecline, now)
+
+	r.store.data.prs[prID] = staged
+	return newReviewerID, nil
+}
+
+func (r *MemoryPullRequestRepository) ApplyReassignments(ctx context.Context, reassignments []models.ReviewerReassignment, reason models.ReviewerChangeReason) error {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	now := memoryNow()
+	staged := make(map[string]*memoryPR)
+	for _, reassignment := range reassignments {
+		record, ok := staged[reassignment.PullRequestID]
+		if !ok {
+			stored, exists := r.store.data.prs[reassignment.PullRequestID]
+			if !exists {
+				return ErrReviewerNotAssigned
+			}
+			record = stored.clone()
+			staged[reassignment.PullRequestID] = record
+		}
+
+		err = r.store.replaceReviewer(record, reassignment.OldReviewerID, reassignment.NewReviewerID, reason, now)
+		if err != nil {
+			return err
+		}
+	}
+
+	for prID, record := range staged {
+		r.store.data.prs[prID] = record
+	}
+	return nil
+}
+
+func (r *MemoryPullRequestRepository) ListPRs(ctx context.Context, filter *models.PRListFilter) ([]models.PullRequest, string, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+
+	cursor, err := decodeCursor(filter.Cursor, filter.Sort+":"+filter.Order)
+	if err != nil {
+		return nil, "", err
+	}
+	var after *models.PullRequest
+	if cursor != nil {
+		after = &models.PullRequest{PullRequestID: cursor.PullRequestID, PullRequestName: cursor.Value}
+		if filter.Sort != models.SortByName {
+			createdAt, parseErr := time.Parse(time.RFC3339Nano, cursor.Value)
+			if parseErr != nil {
+				return nil, "", ErrInvalidCursor
+			}
+			after.CreatedAt = &createdAt
+		}
+	}
+
+	prs := []models.PullRequest{}
+	for _, record := range r.store.data.prs {
+		pr := r.store.pullRequest(record)
+		if !matchesPRListFilter(filter, record, pr) {
+			continue
+		}
+		if after != nil && comparePRListOrder(filter, pr, after) <= 0 {
+			continue
+		}
+		prs = append(prs, *pr)
+	}
+	slices.SortFunc(prs, func(a, b models.PullRequest) int {
+		return comparePRListOrder(filter, &a, &b)
+	})
+
+	var nextCursor string
+	if len(prs) > filter.Limit {
+		prs = prs[:filter.Limit]
+		nextCursor = prListCursor(filter, &prs[len(prs)-1])
+	}
+
+	return prs, nextCursor, nil
+}
+
+func matchesPRListFilter(filter *models.PRListFilter, record *memoryPR, pr *models.PullRequest) bool {
+	if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, pr.Status) {
+		return false
+	}
+	if filter.AuthorID != "" && pr.AuthorID != filter.AuthorID {
+		return false
+	}
+	if filter.ReviewerID != "" && record.reviewerIndex(filter.ReviewerID) < 0 {
+		return false
+	}
+	if filter.TeamName != "" && pr.AuthorTeam != filter.TeamName {
+		return false
+	}
+	if filter.CreatedFrom != nil && pr.CreatedAt.Before(*filter.CreatedFrom) {
+		return false
+	}
+	if filter.CreatedTo != nil && !pr.CreatedAt.Before(*filter.CreatedTo) {
+		return false
+	}
+	if filter.MergedFrom != nil && (pr.MergedAt == nil || pr.MergedAt.Before(*filter.MergedFrom)) {
+		return false
+	}
+	if filter.MergedTo != nil && (pr.MergedAt == nil || !pr.MergedAt.Before(*filter.MergedTo)) {
+		return false
+	}
+	if filter.NameContains != "" && !strings.Contains(strings.ToLower(pr.PullRequestName), strings.ToLower(filter.NameContains)) {
+		return false
+	}
+	return true
+}
+
+func comparePRListOrder(filter *models.PRListFilter, a, b *models.PullRequest) int {
+	var c int
+	switch filter.Sort {
+	case models.SortByName:
+		c = strings.Compare(a.PullRequestName, b.PullRequestName)
+	default:
+		c = a.CreatedAt.Compare(*b.CreatedAt)
+	}
+	if c == 0 {
+		c = strings.Compare(a.PullRequestID, b.PullRequestID)
+	}
+	if filter.Order == models.SortDesc {
+		return -c
+	}
+	return c
+}
+
+func (r *MemoryPullRequestRepository) GetReviewerChanges(ctx context.Context, prID string) ([]models.ReviewerChange, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	changes := []models.ReviewerChange{}
+	if record, ok := r.store.data.prs[prID]; ok {
+		changes = append(changes, record.changes...)
+	}
+	return changes, nil
+}
+
+func (r *MemoryPullRequestRepository) SetReviewState(ctx context.Context, prID, reviewerID string, state models.ReviewState) error {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	record, ok := r.store.data.prs[prID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	idx := record.reviewerIndex(reviewerID)
+	if idx < 0 {
+		return sql.ErrNoRows
+	}
+
+	record.reviewers[idx].State = state
+	record.reviewers[idx].ReviewedAt = timePtr(memoryNow())
+	return nil
+}
+
+func (r *MemoryPullRequestRepository) GetPRsByReviewer(ctx context.Context, reviewerID string, filter *models.ReviewListFilter) ([]models.PullRequestShort, string, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+
+	cursor, err := decodeCursor(filter.Cursor, reviewCursorSort)
+	if err != nil {
+		return nil, "", err
+	}
+	var cursorCreatedAt time.Time
+	if cursor != nil {
+		cursorCreatedAt, err = time.Parse(time.RFC3339Nano, cursor.Value)
+		if err != nil {
+			return nil, "", ErrInvalidCursor
+		}
+	}
+
+	var prs []models.PullRequestShort
+	for _, record := range r.store.data.prs {
+		idx := record.reviewerIndex(reviewerID)
+		if idx < 0 {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, record.pr.Status) {
+			continue
+		}
+		pr := models.PullRequestShort{
+			PullRequestID:   record.pr.PullRequestID,
+			PullRequestName: record.pr.PullRequestName,
+			AuthorID:        record.pr.AuthorID,
+			Status:          record.pr.Status,
+			CreatedAt:       record.pr.CreatedAt,
+			AssignedAt:      record.reviewers[idx].AssignedAt,
+		}
+		if cursor != nil && compareReviewOrder(&pr, cursorCreatedAt, cursor.PullRequestID) >= 0 {
+			continue
+		}
+		prs = append(prs, pr)
+	}
+	slices.SortFunc(prs, func(a, b models.PullRequestShort) int {
+		return compareReviewOrder(&a, *b.CreatedAt, b.PullRequestID)
+	})
+
+	var nextCursor string
+	if filter.Limit > 0 && len(prs) > filter.Limit {
+		prs = prs[:filter.Limit]
+		last := prs[len(prs)-1]
+		nextCursor = encodeCursor(pageCursor{
+			Sort:          reviewCursorSort,
+			Value:         last.CreatedAt.Format(time.RFC3339Nano),
+			PullRequestID: last.PullRequestID,
+		})
+	}
+
+	return prs, nextCursor, nil
+}
+
+func compareReviewOrder(pr *models.PullRequestShort, createdAt time.Time, prID string) int {
+	if c := pr.CreatedAt.Compare(createdAt); c != 0 {
+		return -c
+	}
+	return -strings.Compare(pr.PullRequestID, prID)
+}
+
+func (r *MemoryPullRequestRepository) GetOpenPRIDsByReviewerTeam(ctx context.Context, teamName string) ([]string, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var prIDs []string
+	for prID, record := range r.store.data.prs {
+		if !record.pr.Status.IsOpen() {
+			continue
+		}
+		if slices.ContainsFunc(record.reviewers, func(reviewer models.Reviewer) bool {
+			return r.store.data.users[reviewer.ReviewerID].TeamName == teamName
+		}) {
+			prIDs = append(prIDs, prID)
+		}
+	}
+	slices.Sort(prIDs)
+
+	return prIDs, nil
+}
+
+func (r *MemoryPullRequestRepository) GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	loads := make(map[string]int, len(userIDs))
+	for _, record := range r.store.data.prs {
+		if !record.pr.Status.IsOpen() {
+			continue
+		}
+		for _, reviewer := range record.reviewers {
+			if slices.Contains(userIDs, reviewer.ReviewerID) {
+				loads[reviewer.ReviewerID]++
+			}
+		}
+	}
+	return loads, nil
+}
+
+func (r *MemoryPullRequestRepository) GetUserStats(ctx context.Context) ([]models.UserStat, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	assigned := make(map[string]int)
+	declines := make(map[string]map[models.DeclineCategory]int)
+	for _, record := range r.store.data.prs {
+		for _, reviewer := range record.reviewers {
+			assigned[reviewer.ReviewerID]++
+		}
+		for _, decline := range record.declines {
+			if declines[decline.reviewerID] == nil {
+				declines[decline.reviewerID] = make(map[models.DeclineCategory]int)
+			}
+			declines[decline.reviewerID][decline.category]++
+		}
+	}
+
+	stats := make([]models.UserStat, 0, len(r.store.data.users))
+	for _, user := range r.store.data.users {
+		stat := models.UserStat{UserID: user.UserID, Username: user.Username, AssignedCount: assigned[user.UserID]}
+		for category, count := range declines[user.UserID] {
+			stat.DeclinedCount += count
+			stat.DeclineCategories = append(stat.DeclineCategories, models.DeclineCategoryStat{Category: category, Count: count})
+		}
+		slices.SortFunc(stat.DeclineCategories, func(a, b models.DeclineCategoryStat) int {
+			if c := cmp.Compare(b.Count, a.Count); c != 0 {
+				return c
+			}
+			return cmp.Compare(a.Category, b.Category)
+		})
+		stats = append(stats, stat)
+	}
+	slices.SortFunc(stats, func(a, b models.UserStat) int {
+		if c := cmp.Compare(b.AssignedCount, a.AssignedCount); c != 0 {
+			return c
+		}
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	return stats, nil
+}
+
+func (r *MemoryPullRequestRepository) GetPRStats(ctx context.Context) (*models.PRStat, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	var stats models.PRStat
+	for _, record := range r.store.data.prs {
+		stats.TotalPRs++
+		switch record.pr.Status {
+		case models.StatusDraft:
+			stats.DraftPRs++
+		case models.StatusOpen, models.StatusReopened:
+			stats.OpenPRs++
+		case models.StatusClosed:
+			stats.ClosedPRs++
+		case models.StatusMerged:
+			stats.MergedPRs++
+		}
+	}
+	return &stats, nil
+}
+
+func (s *MemoryStore) pullRequest(record *memoryPR) *models.PullRequest {
+	pr := record.pr
+	pr.AuthorTeam = s.data.users[pr.AuthorID].TeamName
+	pr.AssignedReviewers = []string{}
+	pr.Reviewers = slices.Clone(record.reviewers)
+	if pr.Reviewers == nil {
+		pr.Reviewers = []models.Reviewer{}
+	}
+	slices.SortFunc(pr.Reviewers, func(a, b models.Reviewer) int {
+		if c := a.AssignedAt.Compare(*b.AssignedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ReviewerID, b.ReviewerID)
+	})
+	for _, reviewer := range pr.Reviewers {
+		pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer.ReviewerID)
+	}
+
+	for _, decline := range record.declines {
+		if !slices.Contains(pr.DeclinedReviewers, decline.reviewerID) {
+			pr.DeclinedReviewers = append(pr.DeclinedReviewers, decline.reviewerID)
+		}
+	}
+	slices.Sort(pr.DeclinedReviewers)
+
+	return &pr
+}
+
+func (s *MemoryStore) addReviewer(record *memoryPR, reviewerID string, now time.Time) error {
+	if _, ok := s.data.users[reviewerID]; !ok {
+		return fmt.Errorf("reviewer %q does not exist", reviewerID)
+	}
+	if record.reviewerIndex(reviewerID) >= 0 {
+		return fmt.Errorf("reviewer %q is already assigned", reviewerID)
+	}
+
+	record.reviewers = append(record.reviewers, models.Reviewer{
+		ReviewerID: reviewerID,
+		State:      models.ReviewPending,
+		AssignedAt: timePtr(now),
+	})
+	return nil
+}
+
+func (s *MemoryStore) replaceReviewer(record *memoryPR, oldReviewerID, newReviewerID string, reason models.ReviewerChangeReason, now time.Time) error {
+	if !record.removeReviewer(oldReviewerID) {
+		return ErrReviewerNotAssigned
+	}
+	if err := s.addReviewer(record, newReviewerID, now); err != nil {
+		return fmt.Errorf("failed to add new reviewer: %w", err)
+	}
+	record.recordChange(oldReviewerID, newReviewerID, reason, now)
+	return nil
+}
+
+func (p *memoryPR) removeReviewer(reviewerID string) bool {
+	idx := p.reviewerIndex(reviewerID)
+	if idx < 0 {
+		return false
+	}
+	p.reviewers = slices.Delete(p.reviewers, idx, idx+1)
+	return true
+}
+
+func (p *memoryPR) recordChange(oldReviewerID, newReviewerID string, reason models.ReviewerChangeReason, now time.Time) {
+	p.changes = append(p.changes, models.ReviewerChange{
+		OldReviewerID: oldReviewerID,
+		NewReviewerID: newReviewerID,
+		Reason:        reason,
+		ChangedAt:     now,
+	})
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+type memoryLockKey struct{}
+
+type memoryDecline struct {
+	reviewerID string
+	category   models.DeclineCategory
+	reason     string
+	declinedAt time.Time
+}
+
+type memoryPR struct {
+	pr        models.PullRequest
+	reviewers []models.Reviewer
+	declines  []memoryDecline
+	changes   []models.ReviewerChange
+}
+
+func (p *memoryPR) clone() *memoryPR {
+	return &memoryPR{
+		pr:        p.pr,
+		reviewers: slices.Clone(p.reviewers),
+		declines:  slices.Clone(p.declines),
+		changes:   slices.Clone(p.changes),
+	}
+}
+
+func (p *memoryPR) reviewerIndex(reviewerID string) int {
+	return slices.IndexFunc(p.reviewers, func(reviewer models.Reviewer) bool {
+		return reviewer.ReviewerID == reviewerID
+	})
+}
+
+type memoryIdempotencyRecord struct {
+	record    models.IdempotencyRecord
+	expiresAt time.Time
+}
+
+type memoryData struct {
+	teams         map[string]bool
+	settings      map[string]models.TeamSettings
+	users         map[string]models.User
+	prs           map[string]*memoryPR
+	absences      map[int64]models.Absence
+	nextAbsenceID int64
+	idempotency   map[string]memoryIdempotencyRecord
+}
+
+func (d *memoryData) clone() memoryData {
+	prs := make(map[string]*memoryPR, len(d.prs))
+	for id, pr := range d.prs {
+		prs[id] = pr.clone()
+	}
+	return memoryData{
+		teams:         maps.Clone(d.teams),
+		settings:      maps.Clone(d.settings),
+		users:         maps.Clone(d.users),
+		prs:           prs,
+		absences:      maps.Clone(d.absences),
+		nextAbsenceID: d.nextAbsenceID,
+		idempotency:   maps.Clone(d.idempotency),
+	}
+}
+
+type MemoryStore struct {
+	mu   sync.Mutex
+	data memoryData
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{data: memoryData{
+		teams:       make(map[string]bool),
+		settings:    make(map[string]models.TeamSettings),
+		users:       make(map[string]models.User),
+		prs:         make(map[string]*memoryPR),
+		absences:    make(map[int64]models.Absence),
+		idempotency: make(map[string]memoryIdempotencyRecord),
+	}}
+}
+
+func (s *MemoryStore) lock(ctx context.Context) (context.Context, func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+	if holder, ok := ctx.Value(memoryLockKey{}).(*MemoryStore); ok && holder == s {
+		return ctx, func() {}, nil
+	}
+
+	s.mu.Lock()
+	return context.WithValue(ctx, memoryLockKey{}, s), s.mu.Unlock, nil
+}
+
+type MemoryTxManager struct {
+	store *MemoryStore
+}
+
+func NewMemoryTxManager(store *MemoryStore) *MemoryTxManager {
+	return &MemoryTxManager{store: store}
+}
+
+func (m *MemoryTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	txCtx, unlock, err := m.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	snapshot := m.store.data.clone()
+	if err = fn(txCtx); err != nil {
+		m.store.data = snapshot
+		return err
+	}
+	return nil
+}
+
+func memoryNow() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+type MemoryTeamRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryTeamRepository(store *MemoryStore) *MemoryTeamRepository {
+	return &MemoryTeamRepository{store: store}
+}
+
+func (r *MemoryTeamRepository) CreateTeam(ctx context.Context, team *models.Team) error {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if r.store.data.teams[team.TeamName] {
+		return fmt.Errorf("failed to create team: team %q already exists", team.TeamName)
+	}
+	r.store.data.teams[team.TeamName] = true
+
+	for _, member := range team.Members {
+		user := r.store.data.users[member.UserID]
+		user.UserID = member.UserID
+		user.Username = member.Username
+		user.TeamName = team.TeamName
+		user.IsActive = member.IsActive
+		r.store.data.users[member.UserID] = user
+	}
+	return nil
+}
+
+func (r *MemoryTeamRepository) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	team := models.Team{TeamName: teamName, Members: []models.TeamMember{}}
+	for _, user := range r.store.data.users {
+		if user.TeamName == teamName {
+			team.Members = append(team.Members, models.TeamMember{
+				UserID:   user.UserID,
+				Username: user.Username,
+				IsActive: user.IsActive,
+			})
+		}
+	}
+	slices.SortFunc(team.Members, func(a, b models.TeamMember) int {
+		return strings.Compare(a.Username, b.Username)
+	})
+
+	if !r.store.data.teams[teamName] && len(team.Members) == 0 {
+		return nil, sql.ErrNoRows
+	}
+	return &team, nil
+}
+
+func (r *MemoryTeamRepository) TeamExists(ctx context.Context, teamName string) (bool, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	return r.store.data.teams[teamName], nil
+}
+
+func (r *MemoryTeamRepository) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	settings, ok := r.store.data.settings[teamName]
+	if !ok {
+		return nil, fmt.Errorf("failed to get team settings: %w", sql.ErrNoRows)
+	}
+	return &settings, nil
+}
+
+func (r *MemoryTeamRepository) UpsertTeamSettings(ctx context.Context, settings *models.TeamSettings) error {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if !r.store.data.teams[settings.TeamName] {
+		return fmt.Errorf("failed to save team settings: team %q does not exist", settings.TeamName)
+	}
+	r.store.data.settings[settings.TeamName] = *settings
+	return nil
+}
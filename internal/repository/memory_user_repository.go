@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+type MemoryUserRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryUserRepository(store *MemoryStore) *MemoryUserRepository {
+	return &MemoryUserRepository{store: store}
+}
+
+func (r *MemoryUserRepository) GetUser(ctx context.Context, userID string) (*models.User, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return r.store.getUser(userID)
+}
+
+func (r *MemoryUserRepository) SetIsActive(ctx context.Context, userID string, isActive bool) (*models.User, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if user, ok := r.store.data.users[userID]; ok {
+		user.IsActive = isActive
+		r.store.data.users[userID] = user
+	}
+	return r.store.getUser(userID)
+}
+
+func (r *MemoryUserRepository) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (*models.User, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	if user, ok := r.store.data.users[userID]; ok {
+		user.MaxOpenReviews = nil
+		if maxOpenReviews != nil {
+			value := *maxOpenReviews
+			user.MaxOpenReviews = &value
+		}
+		r.store.data.users[userID] = user
+	}
+	return r.store.getUser(userID)
+}
+
+func (r *MemoryUserRepository) GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]models.User, error) {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	now := memoryNow()
+	absent := make(map[string]bool)
+	for _, absence := range r.store.data.absences {
+		if !absence.StartsAt.After(now) && absence.EndsAt.After(now) {
+			absent[absence.UserID] = true
+		}
+	}
+
+	var users []models.User
+	for _, user := range r.store.data.users {
+		if user.TeamName != teamName || !user.IsActive || user.UserID == excludeUserID || absent[user.UserID] {
+			continue
+		}
+		users = append(users, copyUser(user))
+	}
+	slices.SortFunc(users, func(a, b models.User) int {
+		return strings.Compare(a.UserID, b.UserID)
+	})
+
+	return users, nil
+}
+
+func (r *MemoryUserRepository) DeactivateTeamMembers(ctx context.Context, teamName string) error {
+	_, unlock, err := r.store.lock(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for userID, user := range r.store.data.users {
+		if user.TeamName == teamName {
+			user.IsActive = false
+			r.store.data.users[userID] = user
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) getUser(userID string) (*models.User, error) {
+	user, ok := s.data.users[userID]
+	if !ok {
+		return nil, fmt.Errorf("failed to get user: %w", sql.ErrNoRows)
+	}
+	user = copyUser(user)
+	return &user, nil
+}
+
+func copyUser(user models.User) models.User {
+	if user.MaxOpenReviews != nil {
+		value := *user.MaxOpenReviews
+		user.MaxOpenReviews = &value
+	}
+	return user
+}
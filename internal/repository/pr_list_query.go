@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const reviewCursorSort = "review"
+
+type pageCursor struct {
+	Sort          string `json:"s"`
+	Value         string `json:"v"`
+	PullRequestID string `json:"id"`
+}
+
+func encodeCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor) //nolint:errcheck
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value, sort string) (*pageCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.Sort != sort || cursor.PullRequestID == "" {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+type prListQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (q *prListQuery) arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *prListQuery) where(condition string, values ...interface{}) {
+	placeholders := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		placeholders = append(placeholders, q.arg(value))
+	}
+	q.conditions = append(q.conditions, fmt.Sprintf(condition, placeholders...))
+}
+
+func (q *prListQuery) whereClause() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return "WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func buildPRListQuery(d dialect, filter *models.PRListFilter) (*prListQuery, error) {
+	q := &prListQuery{}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, string(status))
+		}
+		q.conditions = append(q.conditions, d.anyOf(q, "p.status", statuses))
+	}
+	if filter.AuthorID != "" {
+		q.where("p.author_id = %s", filter.AuthorID)
+	}
+	if filter.ReviewerID != "" {
+		q.where(`EXISTS (
+			SELECT 1 FROM pull_request_reviewers prr
+			WHERE prr.pull_request_id = p.pull_request_id AND prr.reviewer_id = %s
+		)`, filter.ReviewerID)
+	}
+	if filter.TeamName != "" {
+		q.where("u.team_name = %s", filter.TeamName)
+	}
+	if filter.CreatedFrom != nil {
+		q.where("p.created_at >= %s", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		q.where("p.created_at < %s", *filter.CreatedTo)
+	}
+	if filter.MergedFrom != nil {
+		q.where("p.merged_at >= %s", *filter.MergedFrom)
+	}
+	if filter.MergedTo != nil {
+		q.where("p.merged_at < %s", *filter.MergedTo)
+	}
+	if filter.NameContains != "" {
+		q.conditions = append(q.conditions, d.containsFold(q, "p.pull_request_name", likeEscaper.Replace(filter.NameContains)))
+	}
+
+	cursor, err := decodeCursor(filter.Cursor, filter.Sort+":"+filter.Order)
+	if err != nil {
+		return nil, err
+	}
+	if cursor != nil {
+		comparison := ">"
+		if filter.Order == models.SortDesc {
+			comparison = "<"
+		}
+
+		switch filter.Sort {
+		case models.SortByName:
+			q.where("(p.pull_request_name, p.pull_request_id) "+comparison+" (%s, %s)", cursor.Value, cursor.PullRequestID)
+		default:
+			createdAt, parseErr := time.Parse(time.RFC3339Nano, cursor.Value)
+			if parseErr != nil {
+				return nil, ErrInvalidCursor
+			}
+			q.where("(p.created_at, p.pull_request_id) "+comparison+" (%s, %s)", createdAt, cursor.PullRequestID)
+		}
+	}
+
+	return q, nil
+}
+
+func prListOrder(filter *models.PRListFilter) string {
+	direction := "ASC"
+	if filter.Order == models.SortDesc {
+		direction = "DESC"
+	}
+
+	switch filter.Sort {
+	case models.SortByName:
+		return fmt.Sprintf("p.pull_request_name %s, p.pull_request_id %s", direction, direction)
+	default:
+		return fmt.Sprintf("p.created_at %s, p.pull_request_id %s", direction, direction)
+	}
+}
+
+func prListCursor(filter *models.PRListFilter, pr *models.PullRequest) string {
+	cursor := pageCursor{Sort: filter.Sort + ":" + filter.Order, PullRequestID: pr.PullRequestID}
+	switch filter.Sort {
+	case models.SortByName:
+		cursor.Value = pr.PullRequestName
+	default:
+		if pr.CreatedAt != nil {
+			cursor.Value = pr.CreatedAt.Format(time.RFC3339Nano)
+		}
+	}
+	return encodeCursor(cursor)
+}
@@ -1,39 +1,59 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/avito/pr-reviewer-service/internal/models"
 )
 
+var (
+	ErrPRAlreadyExists     = errors.New("pull request already exists")
+	ErrReviewerNotAssigned = errors.New("reviewer not assigned")
+
+	ErrReviewerAlreadyAssigned = errors.New("reviewer already assigned")
+)
+
 type PullRequestRepository struct {
-	db *sql.DB
+	db      *sql.DB
+	dialect dialect
 }
 
 func NewPullRequestRepository(db *sql.DB) *PullRequestRepository {
-	return &PullRequestRepository{db: db}
+	return &PullRequestRepository{db: db, dialect: dialectOf(db)}
 }
 
-func (r *PullRequestRepository) CreatePR(pr *models.PullRequest) error {
-	tx, err := r.db.Begin()
+func (r *PullRequestRepository) CreatePR(ctx context.Context, pr *models.PullRequest, selectReviewers func(ctx context.Context) ([]string, error)) error {
+	txCtx, tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback() //nolint:errcheck
 
 	now := time.Now()
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO pull_requests (pull_request_id, pull_request_name, author_id, status, created_at)
 		VALUES ($1, $2, $3, $4, $5)
 	`, pr.PullRequestID, pr.PullRequestName, pr.AuthorID, pr.Status, now)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrPRAlreadyExists
+		}
 		return fmt.Errorf("failed to create PR: %w", err)
 	}
 
+	if selectReviewers != nil {
+		pr.AssignedReviewers, err = selectReviewers(txCtx)
+		if err != nil {
+			return err
+		}
+	}
+
 	for _, reviewerID := range pr.AssignedReviewers {
-		_, err = tx.Exec(`
+		_, err = tx.ExecContext(ctx, `
 			INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, assigned_at)
 			VALUES ($1, $2, $3)
 		`, pr.PullRequestID, reviewerID, now)
@@ -45,21 +65,24 @@ func (r *PullRequestRepository) CreatePR(pr *models.PullRequest) error {
 	return tx.Commit()
 }
 
-func (r *PullRequestRepository) GetPR(prID string) (*models.PullRequest, error) {
+func (r *PullRequestRepository) GetPR(ctx context.Context, prID string) (*models.PullRequest, error) {
 	var pr models.PullRequest
-	var createdAt, mergedAt sql.NullTime
+	var createdAt, mergedAt, closedAt sql.NullTime
 
-	err := r.db.QueryRow(`
-		SELECT pull_request_id, pull_request_name, author_id, status, created_at, merged_at
-		FROM pull_requests
-		WHERE pull_request_id = $1
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, u.team_name, p.status, p.created_at, p.merged_at, p.closed_at
+		FROM pull_requests p
+		INNER JOIN users u ON u.user_id = p.author_id
+		WHERE p.pull_request_id = $1
 	`, prID).Scan(
 		&pr.PullRequestID,
 		&pr.PullRequestName,
 		&pr.AuthorID,
+		&pr.AuthorTeam,
 		&pr.Status,
 		&createdAt,
 		&mergedAt,
+		&closedAt,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PR: %w", err)
@@ -71,117 +94,670 @@ func (r *PullRequestRepository) GetPR(prID string) (*models.PullRequest, error)
 	if mergedAt.Valid {
 		pr.MergedAt = &mergedAt.Time
 	}
+	if closedAt.Valid {
+		pr.ClosedAt = &closedAt.Time
+	}
+
+	if err := r.attachReviewers(ctx, []*models.PullRequest{&pr}); err != nil {
+		return nil, err
+	}
+
+	return &pr, nil
+}
+
+func (r *PullRequestRepository) ListPRs(ctx context.Context, filter *models.PRListFilter) ([]models.PullRequest, string, error) {
+	q, err := buildPRListQuery(r.dialect, filter)
+	if err != nil {
+		return nil, "", err
+	}
 
-	rows, err := r.db.Query(`
-		SELECT reviewer_id
+	query := fmt.Sprintf(`
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, u.team_name, p.status, p.created_at, p.merged_at, p.closed_at
+		FROM pull_requests p
+		INNER JOIN users u ON u.user_id = p.author_id
+		%s
+		ORDER BY %s
+		LIMIT %s
+	`, q.whereClause(), prListOrder(filter), q.arg(filter.Limit+1))
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, q.args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to list PRs: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	prs := []models.PullRequest{}
+	for rows.Next() {
+		var pr models.PullRequest
+		var createdAt, mergedAt, closedAt sql.NullTime
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.AuthorTeam, &pr.Status, &createdAt, &mergedAt, &closedAt); err != nil {
+			return nil, "", fmt.Errorf("failed to scan PR: %w", err)
+		}
+		if createdAt.Valid {
+			pr.CreatedAt = &createdAt.Time
+		}
+		if mergedAt.Valid {
+			pr.MergedAt = &mergedAt.Time
+		}
+		if closedAt.Valid {
+			pr.ClosedAt = &closedAt.Time
+		}
+		prs = append(prs, pr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to list PRs: %w", err)
+	}
+
+	var nextCursor string
+	if len(prs) > filter.Limit {
+		prs = prs[:filter.Limit]
+		nextCursor = prListCursor(filter, &prs[len(prs)-1])
+	}
+
+	page := make([]*models.PullRequest, 0, len(prs))
+	for i := range prs {
+		page = append(page, &prs[i])
+	}
+	if err := r.attachReviewers(ctx, page); err != nil {
+		return nil, "", err
+	}
+
+	return prs, nextCursor, nil
+}
+
+func (r *PullRequestRepository) attachReviewers(ctx context.Context, prs []*models.PullRequest) error {
+	if len(prs) == 0 {
+		return nil
+	}
+
+	byID := make(map[string]*models.PullRequest, len(prs))
+	prIDs := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		pr.AssignedReviewers = []string{}
+		pr.Reviewers = []models.Reviewer{}
+		byID[pr.PullRequestID] = pr
+		prIDs = append(prIDs, pr.PullRequestID)
+	}
+
+	q := &prListQuery{}
+	rows, err := conn(ctx, r.db).QueryContext(ctx, fmt.Sprintf(`
+		SELECT pull_request_id, reviewer_id, review_state, assigned_at, reviewed_at
 		FROM pull_request_reviewers
-		WHERE pull_request_id = $1
-		ORDER BY assigned_at
-	`, prID)
+		WHERE %s
+		ORDER BY assigned_at, reviewer_id
+	`, r.dialect.anyOf(q, "pull_request_id", prIDs)), q.args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get reviewers: %w", err)
+		return fmt.Errorf("failed to get reviewers: %w", err)
 	}
 	defer rows.Close() //nolint:errcheck
 
-	pr.AssignedReviewers = []string{}
 	for rows.Next() {
-		var reviewerID string
-		if err := rows.Scan(&reviewerID); err != nil {
-			return nil, fmt.Errorf("failed to scan reviewer: %w", err)
+		var prID string
+		var reviewer models.Reviewer
+		var assignedAt, reviewedAt sql.NullTime
+		if err = rows.Scan(&prID, &reviewer.ReviewerID, &reviewer.State, &assignedAt, &reviewedAt); err != nil {
+			return fmt.Errorf("failed to scan reviewer: %w", err)
+		}
+		if assignedAt.Valid {
+			reviewer.AssignedAt = &assignedAt.Time
+		}
+		if reviewedAt.Valid {
+			reviewer.ReviewedAt = &reviewedAt.Time
+		}
+		pr := byID[prID]
+		pr.AssignedReviewers = append(pr.AssignedReviewers, reviewer.ReviewerID)
+		pr.Reviewers = append(pr.Reviewers, reviewer)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to get reviewers: %w", err)
+	}
+
+	q = &prListQuery{}
+	declineRows, err := conn(ctx, r.db).QueryContext(ctx, fmt.Sprintf(`
+		SELECT DISTINCT pull_request_id, reviewer_id
+		FROM pull_request_declines
+		WHERE %s
+		ORDER BY pull_request_id, reviewer_id
+	`, r.dialect.anyOf(q, "pull_request_id", prIDs)), q.args...)
+	if err != nil {
+		return fmt.Errorf("failed to get declines: %w", err)
+	}
+	defer declineRows.Close() //nolint:errcheck
+
+	for declineRows.Next() {
+		var prID, reviewerID string
+		if err := declineRows.Scan(&prID, &reviewerID); err != nil {
+			return fmt.Errorf("failed to scan decline: %w", err)
 		}
-		pr.AssignedReviewers = append(pr.AssignedReviewers, reviewerID)
+		byID[prID].DeclinedReviewers = append(byID[prID].DeclinedReviewers, reviewerID)
 	}
 
-	return &pr, rows.Err()
+	return declineRows.Err()
 }
 
-func (r *PullRequestRepository) PRExists(prID string) (bool, error) {
-	var exists bool
-	err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM pull_requests WHERE pull_request_id = $1)", prID).Scan(&exists)
-	return exists, err
+func (r *PullRequestRepository) GetPRForUpdate(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return r.lockPR(ctx, conn(ctx, r.db), prID)
 }
 
-func (r *PullRequestRepository) MergePR(prID string) (*models.PullRequest, error) {
-	now := time.Now()
-	_, err := r.db.Exec(`
+func (r *PullRequestRepository) lockPR(ctx context.Context, tx queryer, prID string) (*models.PullRequest, error) {
+	var lockedID string
+	err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		SELECT pull_request_id FROM pull_requests
+		WHERE pull_request_id = $1
+		%s
+	`, r.dialect.forUpdate()), prID).Scan(&lockedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock PR: %w", err)
+	}
+
+	return r.GetPR(ctx, prID)
+}
+
+func (r *PullRequestRepository) lockUser(ctx context.Context, tx queryer, userID string) error {
+	var lockedID string
+	err := tx.QueryRowContext(ctx, fmt.Sprintf(`
+		SELECT user_id FROM users
+		WHERE user_id = $1
+		%s
+	`, r.dialect.forUpdate()), userID).Scan(&lockedID)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to lock user: %w", err)
+	}
+	return nil
+}
+
+func (r *PullRequestRepository) MergePR(ctx context.Context, prID string, check func(ctx context.Context, pr *models.PullRequest) error) (*models.PullRequest, error) {
+	txCtx, tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	pr, err := r.lockPR(txCtx, tx, prID)
+	if err != nil {
+		return nil, err
+	}
+	if pr.Status == models.StatusMerged {
+		return pr, nil
+	}
+
+	if err = check(txCtx, pr); err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE pull_requests
 		SET status = 'MERGED', merged_at = $1
-		WHERE pull_request_id = $2 AND status != 'MERGED'
-	`, now, prID)
+		WHERE pull_request_id = $2
+	`, time.Now(), prID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to merge PR: %w", err)
 	}
 
-	return r.GetPR(prID)
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return r.GetPR(ctx, prID)
 }
 
-func (r *PullRequestRepository) ReassignReviewer(prID, oldReviewerID, newReviewerID string) error {
-	tx, err := r.db.Begin()
+func (r *PullRequestRepository) TransitionPR(ctx context.Context, prID string, from, to models.PullRequestStatus, reviewerIDs []string) error {
+	_, tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback() //nolint:errcheck
 
+	result, err := tx.ExecContext(ctx, `
+		UPDATE pull_requests
+		SET status = $1,
+			closed_at = CASE WHEN $1 = 'CLOSED' THEN CURRENT_TIMESTAMP ELSE NULL END
+		WHERE pull_request_id = $2 AND status = $3
+	`, to, prID, from)
+	if err != nil {
+		return fmt.Errorf("failed to update PR status: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to update PR status: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+
+	for _, reviewerID := range reviewerIDs {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, assigned_at)
+			VALUES ($1, $2, CURRENT_TIMESTAMP)
+		`, prID, reviewerID)
+		if err != nil {
+			return fmt.Errorf("failed to assign reviewer: %w", err)
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *PullRequestRepository) ReassignReviewer(ctx context.Context, prID, oldReviewerID string, pick func(ctx context.Context, pr *models.PullRequest) (string, error)) (string, error) {
+	txCtx, tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	pr, err := r.lockPR(txCtx, tx, prID)
+	if err != nil {
+		return "", err
+	}
+
+	newReviewerID, err := pick(txCtx, pr)
+	if err != nil {
+		return "", err
+	}
+
 	var exists bool
-	err = tx.QueryRow(`
+	err = tx.QueryRowContext(ctx, `
 		SELECT EXISTS(
 			SELECT 1 FROM pull_request_reviewers
 			WHERE pull_request_id = $1 AND reviewer_id = $2
 		)
 	`, prID, oldReviewerID).Scan(&exists)
 	if err != nil {
-		return fmt.Errorf("failed to check reviewer assignment: %w", err)
+		return "", fmt.Errorf("failed to check reviewer assignment: %w", err)
 	}
 	if !exists {
-		return fmt.Errorf("reviewer not assigned")
+		return "", ErrReviewerNotAssigned
 	}
 
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		DELETE FROM pull_request_reviewers
 		WHERE pull_request_id = $1 AND reviewer_id = $2
 	`, prID, oldReviewerID)
 	if err != nil {
-		return fmt.Errorf("failed to remove old reviewer: %w", err)
+		return "", fmt.Errorf("failed to remove old reviewer: %w", err)
 	}
 
-	_, err = tx.Exec(`
+	_, err = tx.ExecContext(ctx, `
 		INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, assigned_at)
 		VALUES ($1, $2, CURRENT_TIMESTAMP)
 	`, prID, newReviewerID)
 	if err != nil {
-		return fmt.Errorf("failed to add new reviewer: %w", err)
+		return "", fmt.Errorf("failed to add new reviewer: %w", err)
+	}
+
+	if err = recordReviewerChange(ctx, tx, prID, oldReviewerID, newReviewerID, models.ChangeReasonReassign); err != nil {
+		return "", err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	return newReviewerID, nil
+}
+
+func (r *PullRequestRepository) AddReviewer(ctx context.Context, prID, reviewerID string, check func(ctx context.Context, pr *models.PullRequest) error) error {
+	txCtx, tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	pr, err := r.lockPR(txCtx, tx, prID)
+	if err != nil {
+		return err
+	}
+	if err = r.lockUser(txCtx, tx, reviewerID); err != nil {
+		return err
+	}
+
+	if err = check(txCtx, pr); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, assigned_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+	`, prID, reviewerID)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return ErrReviewerAlreadyAssigned
+		}
+		return fmt.Errorf("failed to add reviewer: %w", err)
+	}
+
+	if err = recordReviewerChange(ctx, tx, prID, "", reviewerID, models.ChangeReasonAdd); err != nil {
+		return err
 	}
 
 	return tx.Commit()
 }
 
-func (r *PullRequestRepository) GetPRsByReviewer(reviewerID string) ([]models.PullRequestShort, error) {
-	rows, err := r.db.Query(`
-		SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status
+func (r *PullRequestRepository) RemoveReviewer(ctx context.Context, prID, reviewerID string, check func(ctx context.Context, pr *models.PullRequest) error) error {
+	txCtx, tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	pr, err := r.lockPR(txCtx, tx, prID)
+	if err != nil {
+		return err
+	}
+	if err = check(txCtx, pr); err != nil {
+		return err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM pull_request_reviewers
+		WHERE pull_request_id = $1 AND reviewer_id = $2
+	`, prID, reviewerID)
+	if err != nil {
+		return fmt.Errorf("failed to remove reviewer: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to remove reviewer: %w", err)
+	}
+	if affected == 0 {
+		return ErrReviewerNotAssigned
+	}
+
+	if err = recordReviewerChange(ctx, tx, prID, reviewerID, "", models.ChangeReasonRemove); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *PullRequestRepository) DeclineReview(ctx context.Context, prID, reviewerID string, category models.DeclineCategory, reason string, pick func(ctx context.Context, pr *models.PullRequest) (string, error)) (string, error) {
+	txCtx, tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	pr, err := r.lockPR(txCtx, tx, prID)
+	if err != nil {
+		return "", err
+	}
+
+	newReviewerID, err := pick(txCtx, pr)
+	if err != nil {
+		return "", err
+	}
+
+	result, err := tx.ExecContext(ctx, `
+		DELETE FROM pull_request_reviewers
+		WHERE pull_request_id = $1 AND reviewer_id = $2
+	`, prID, reviewerID)
+	if err != nil {
+		return "", fmt.Errorf("failed to remove reviewer: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return "", fmt.Errorf("failed to remove reviewer: %w", err)
+	}
+	if affected == 0 {
+		return "", ErrReviewerNotAssigned
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO pull_request_declines (pull_request_id, reviewer_id, category, reason, declined_at)
+		VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
+	`, prID, reviewerID, category, reason)
+	if err != nil {
+		return "", fmt.Errorf("failed to record decline: %w", err)
+	}
+
+	if newReviewerID != "" {
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, assigned_at)
+			VALUES ($1, $2, CURRENT_TIMESTAMP)
+		`, prID, newReviewerID)
+		if err != nil {
+			return "", fmt.Errorf("failed to add new reviewer: %w", err)
+		}
+	}
+
+	if err = recordReviewerChange(ctx, tx, prID, reviewerID, newReviewerID, models.ChangeReasonDecline); err != nil {
+		return "", err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return "", err
+	}
+	return newReviewerID, nil
+}
+
+func (r *PullRequestRepository) ApplyReassignments(ctx context.Context, reassignments []models.ReviewerReassignment, reason models.ReviewerChangeReason) error {
+	_, tx, err := beginTx(ctx, r.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, reassignment := range reassignments {
+		result, execErr := tx.ExecContext(ctx, `
+			DELETE FROM pull_request_reviewers
+			WHERE pull_request_id = $1 AND reviewer_id = $2
+		`, reassignment.PullRequestID, reassignment.OldReviewerID)
+		if execErr != nil {
+			return fmt.Errorf("failed to remove old reviewer: %w", execErr)
+		}
+		if affected, affectedErr := result.RowsAffected(); affectedErr != nil {
+			return fmt.Errorf("failed to remove old reviewer: %w", affectedErr)
+		} else if affected == 0 {
+			return ErrReviewerNotAssigned
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO pull_request_reviewers (pull_request_id, reviewer_id, assigned_at)
+			VALUES ($1, $2, CURRENT_TIMESTAMP)
+		`, reassignment.PullRequestID, reassignment.NewReviewerID)
+		if err != nil {
+			return fmt.Errorf("failed to add new reviewer: %w", err)
+		}
+
+		err = recordReviewerChange(ctx, tx, reassignment.PullRequestID, reassignment.OldReviewerID, reassignment.NewReviewerID, reason)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func recordReviewerChange(ctx context.Context, tx queryer, prID, oldReviewerID, newReviewerID string, reason models.ReviewerChangeReason) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO pull_request_reviewer_changes (pull_request_id, old_reviewer_id, new_reviewer_id, reason, changed_at)
+		VALUES ($1, NULLIF($2, ''), NULLIF($3, ''), $4, CURRENT_TIMESTAMP)
+	`, prID, oldReviewerID, newReviewerID, reason)
+	if err != nil {
+		return fmt.Errorf("failed to record reviewer change: %w", err)
+	}
+	return nil
+}
+
+func (r *PullRequestRepository) GetReviewerChanges(ctx context.Context, prID string) ([]models.ReviewerChange, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT COALESCE(old_reviewer_id, ''), COALESCE(new_reviewer_id, ''), reason, changed_at
+		FROM pull_request_reviewer_changes
+		WHERE pull_request_id = $1
+		ORDER BY changed_at, change_id
+	`, prID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviewer changes: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	changes := []models.ReviewerChange{}
+	for rows.Next() {
+		var change models.ReviewerChange
+		if err := rows.Scan(&change.OldReviewerID, &change.NewReviewerID, &change.Reason, &change.ChangedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan reviewer change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+func (r *PullRequestRepository) SetReviewState(ctx context.Context, prID, reviewerID string, state models.ReviewState) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE pull_request_reviewers
+		SET review_state = $1, reviewed_at = CURRENT_TIMESTAMP
+		WHERE pull_request_id = $2 AND reviewer_id = $3
+	`, state, prID, reviewerID)
+	if err != nil {
+		return fmt.Errorf("failed to set review state: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to set review state: %w", err)
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *PullRequestRepository) GetPRsByReviewer(ctx context.Context, reviewerID string, filter *models.ReviewListFilter) ([]models.PullRequestShort, string, error) {
+	q := &prListQuery{}
+	q.where("prr.reviewer_id = %s", reviewerID)
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, string(status))
+		}
+		q.conditions = append(q.conditions, r.dialect.anyOf(q, "p.status", statuses))
+	}
+
+	cursor, err := decodeCursor(filter.Cursor, reviewCursorSort)
+	if err != nil {
+		return nil, "", err
+	}
+	if cursor != nil {
+		createdAt, parseErr := time.Parse(time.RFC3339Nano, cursor.Value)
+		if parseErr != nil {
+			return nil, "", ErrInvalidCursor
+		}
+		q.where("(p.created_at, p.pull_request_id) < (%s, %s)", createdAt, cursor.PullRequestID)
+	}
+
+	limit := ""
+	if filter.Limit > 0 {
+		limit = "LIMIT " + q.arg(filter.Limit+1)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, fmt.Sprintf(`
+		SELECT p.pull_request_id, p.pull_request_name, p.author_id, p.status, p.created_at, prr.assigned_at
 		FROM pull_requests p
 		INNER JOIN pull_request_reviewers prr ON p.pull_request_id = prr.pull_request_id
-		WHERE prr.reviewer_id = $1
-		ORDER BY p.created_at DESC
-	`, reviewerID)
+		%s
+		ORDER BY p.created_at DESC, p.pull_request_id DESC
+		%s
+	`, q.whereClause(), limit), q.args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get PRs by reviewer: %w", err)
+		return nil, "", fmt.Errorf("failed to get PRs by reviewer: %w", err)
 	}
 	defer rows.Close() //nolint:errcheck
 
 	var prs []models.PullRequestShort
 	for rows.Next() {
 		var pr models.PullRequestShort
-		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status); err != nil {
-			return nil, fmt.Errorf("failed to scan PR: %w", err)
+		var createdAt, assignedAt sql.NullTime
+		if err := rows.Scan(&pr.PullRequestID, &pr.PullRequestName, &pr.AuthorID, &pr.Status, &createdAt, &assignedAt); err != nil {
+			return nil, "", fmt.Errorf("failed to scan PR: %w", err)
+		}
+		if createdAt.Valid {
+			pr.CreatedAt = &createdAt.Time
+		}
+		if assignedAt.Valid {
+			pr.AssignedAt = &assignedAt.Time
 		}
 		prs = append(prs, pr)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("failed to get PRs by reviewer: %w", err)
+	}
+
+	var nextCursor string
+	if filter.Limit > 0 && len(prs) > filter.Limit {
+		prs = prs[:filter.Limit]
+		last := prs[len(prs)-1]
+		next := pageCursor{Sort: reviewCursorSort, PullRequestID: last.PullRequestID}
+		if last.CreatedAt != nil {
+			next.Value = last.CreatedAt.Format(time.RFC3339Nano)
+		}
+		nextCursor = encodeCursor(next)
+	}
 
-	return prs, rows.Err()
+	return prs, nextCursor, nil
 }
 
-func (r *PullRequestRepository) GetUserStats() ([]models.UserStat, error) {
-	rows, err := r.db.Query(`
+func (r *PullRequestRepository) GetOpenPRIDsByReviewerTeam(ctx context.Context, teamName string) ([]string, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT DISTINCT p.pull_request_id
+		FROM pull_requests p
+		INNER JOIN pull_request_reviewers prr ON p.pull_request_id = prr.pull_request_id
+		INNER JOIN users u ON u.user_id = prr.reviewer_id
+		WHERE u.team_name = $1 AND p.status IN ('OPEN', 'REOPENED')
+		ORDER BY p.pull_request_id
+	`, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open PRs by reviewer team: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	var prIDs []string
+	for rows.Next() {
+		var prID string
+		if err := rows.Scan(&prID); err != nil {
+			return nil, fmt.Errorf("failed to scan PR id: %w", err)
+		}
+		prIDs = append(prIDs, prID)
+	}
+
+	return prIDs, rows.Err()
+}
+
+func (r *PullRequestRepository) GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error) {
+	loads := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return loads, nil
+	}
+
+	q := &prListQuery{}
+	rows, err := conn(ctx, r.db).QueryContext(ctx, fmt.Sprintf(`
+		SELECT prr.reviewer_id, COUNT(*)
+		FROM pull_request_reviewers prr
+		INNER JOIN pull_requests p ON p.pull_request_id = prr.pull_request_id
+		WHERE %s AND p.status IN ('OPEN', 'REOPENED')
+		GROUP BY prr.reviewer_id
+	`, r.dialect.anyOf(q, "prr.reviewer_id", userIDs)), q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open review counts: %w", err)
+	}
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		var reviewerID string
+		var load int
+		if err := rows.Scan(&reviewerID, &load); err != nil {
+			return nil, fmt.Errorf("failed to scan open review count: %w", err)
+		}
+		loads[reviewerID] = load
+	}
+
+	return loads, rows.Err()
+}
+
+func (r *PullRequestRepository) GetUserStats(ctx context.Context) ([]models.UserStat, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
 		SELECT u.user_id, u.username, COUNT(prr.reviewer_id) as assigned_count
 		FROM users u
 		LEFT JOIN pull_request_reviewers prr ON u.user_id = prr.reviewer_id
@@ -194,26 +770,58 @@ func (r *PullRequestRepository) GetUserStats() ([]models.UserStat, error) {
 	defer rows.Close() //nolint:errcheck
 
 	var stats []models.UserStat
+	byUser := make(map[string]int)
 	for rows.Next() {
 		var stat models.UserStat
-		if err := rows.Scan(&stat.UserID, &stat.Username, &stat.AssignedCount); err != nil {
+		if err = rows.Scan(&stat.UserID, &stat.Username, &stat.AssignedCount); err != nil {
 			return nil, fmt.Errorf("failed to scan user stat: %w", err)
 		}
+		byUser[stat.UserID] = len(stats)
 		stats = append(stats, stat)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get user stats: %w", err)
+	}
+
+	declineRows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT reviewer_id, category, COUNT(*) as declined_count
+		FROM pull_request_declines
+		GROUP BY reviewer_id, category
+		ORDER BY reviewer_id, declined_count DESC, category
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decline stats: %w", err)
+	}
+	defer declineRows.Close() //nolint:errcheck
+
+	for declineRows.Next() {
+		var reviewerID string
+		var category models.DeclineCategoryStat
+		if err := declineRows.Scan(&reviewerID, &category.Category, &category.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan decline stat: %w", err)
+		}
+		idx, ok := byUser[reviewerID]
+		if !ok {
+			continue
+		}
+		stats[idx].DeclinedCount += category.Count
+		stats[idx].DeclineCategories = append(stats[idx].DeclineCategories, category)
+	}
 
-	return stats, rows.Err()
+	return stats, declineRows.Err()
 }
 
-func (r *PullRequestRepository) GetPRStats() (*models.PRStat, error) {
+func (r *PullRequestRepository) GetPRStats(ctx context.Context) (*models.PRStat, error) {
 	var stats models.PRStat
-	err := r.db.QueryRow(`
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
 		SELECT 
 			COUNT(*) as total,
-			COUNT(*) FILTER (WHERE status = 'OPEN') as open,
-			COUNT(*) FILTER (WHERE status = 'MERGED') as merged
+			COUNT(CASE WHEN status = 'DRAFT' THEN 1 END) as draft,
+			COUNT(CASE WHEN status IN ('OPEN', 'REOPENED') THEN 1 END) as open,
+			COUNT(CASE WHEN status = 'CLOSED' THEN 1 END) as closed,
+			COUNT(CASE WHEN status = 'MERGED' THEN 1 END) as merged
 		FROM pull_requests
-	`).Scan(&stats.TotalPRs, &stats.OpenPRs, &stats.MergedPRs)
+	`).Scan(&stats.TotalPRs, &stats.DraftPRs, &stats.OpenPRs, &stats.ClosedPRs, &stats.MergedPRs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get PR stats: %w", err)
 	}
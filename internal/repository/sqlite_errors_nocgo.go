@@ -0,0 +1,7 @@
+//go:build !cgo
+
+package repository
+
+func isSQLiteUniqueViolation(error) bool {
+	return false
+}
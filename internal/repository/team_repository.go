@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -15,20 +16,20 @@ func NewTeamRepository(db *sql.DB) *TeamRepository {
 	return &TeamRepository{db: db}
 }
 
-func (r *TeamRepository) CreateTeam(team *models.Team) error {
-	tx, err := r.db.Begin()
+func (r *TeamRepository) CreateTeam(ctx context.Context, team *models.Team) error {
+	_, tx, err := beginTx(ctx, r.db)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback() //nolint:errcheck
 
-	_, err = tx.Exec("INSERT INTO teams (team_name) VALUES ($1)", team.TeamName)
+	_, err = tx.ExecContext(ctx, "INSERT INTO teams (team_name) VALUES ($1)", team.TeamName)
 	if err != nil {
 		return fmt.Errorf("failed to create team: %w", err)
 	}
 
 	for _, member := range team.Members {
-		_, err = tx.Exec(`
+		_, err = tx.ExecContext(ctx, `
 			INSERT INTO users (user_id, username, team_name, is_active)
 			VALUES ($1, $2, $3, $4)
 			ON CONFLICT (user_id) 
@@ -42,8 +43,8 @@ func (r *TeamRepository) CreateTeam(team *models.Team) error {
 	return tx.Commit()
 }
 
-func (r *TeamRepository) GetTeam(teamName string) (*models.Team, error) {
-	rows, err := r.db.Query(`
+func (r *TeamRepository) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
 		SELECT user_id, username, is_active
 		FROM users
 		WHERE team_name = $1
@@ -71,7 +72,7 @@ func (r *TeamRepository) GetTeam(teamName string) (*models.Team, error) {
 	}
 
 	var exists bool
-	err = r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
+	err = conn(ctx, r.db).QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
 	if err != nil {
 		return nil, fmt.Errorf("failed to check team existence: %w", err)
 	}
@@ -83,15 +84,15 @@ func (r *TeamRepository) GetTeam(teamName string) (*models.Team, error) {
 	return &team, nil
 }
 
-func (r *TeamRepository) TeamExists(teamName string) (bool, error) {
+func (r *TeamRepository) TeamExists(ctx context.Context, teamName string) (bool, error) {
 	var exists bool
-	err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
+	err := conn(ctx, r.db).QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM teams WHERE team_name = $1)", teamName).Scan(&exists)
 	return exists, err
 }
 
-func (r *TeamRepository) GetTeamMembers(teamName string) ([]models.User, error) {
-	rows, err := r.db.Query(`
-		SELECT user_id, username, team_name, is_active
+func (r *TeamRepository) GetTeamMembers(ctx context.Context, teamName string) ([]models.User, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, `
+		SELECT user_id, username, team_name, is_active, max_open_reviews
 		FROM users
 		WHERE team_name = $1
 	`, teamName)
@@ -103,11 +104,54 @@ func (r *TeamRepository) GetTeamMembers(teamName string) ([]models.User, error)
 	var users []models.User
 	for rows.Next() {
 		var user models.User
-		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+		var maxOpenReviews sql.NullInt64
+		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &maxOpenReviews); err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
+		user.MaxOpenReviews = nullableInt(maxOpenReviews)
 		users = append(users, user)
 	}
 
 	return users, rows.Err()
 }
+
+func (r *TeamRepository) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	var settings models.TeamSettings
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT team_name, reviewer_count, selection_strategy, min_approvals,
+			block_on_changes_requested, require_all_approved, sla_hours
+		FROM team_settings
+		WHERE team_name = $1
+	`, teamName).Scan(
+		&settings.TeamName,
+		&settings.ReviewerCount,
+		&settings.SelectionStrategy,
+		&settings.MinApprovals,
+		&settings.BlockOnChangesRequested,
+		&settings.RequireAllApproved,
+		&settings.SLAHours,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team settings: %w", err)
+	}
+	return &settings, nil
+}
+
+func (r *TeamRepository) UpsertTeamSettings(ctx context.Context, settings *models.TeamSettings) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		INSERT INTO team_settings (
+			team_name, reviewer_count, selection_strategy, min_approvals,
+			block_on_changes_requested, require_all_approved, sla_hours
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (team_name)
+		DO UPDATE SET reviewer_count = $2, selection_strategy = $3, min_approvals = $4,
+			block_on_changes_requested = $5, require_all_approved = $6, sla_hours = $7,
+			updated_at = CURRENT_TIMESTAMP
+	`, settings.TeamName, settings.ReviewerCount, settings.SelectionStrategy, settings.MinApprovals,
+		settings.BlockOnChangesRequested, settings.RequireAllApproved, settings.SLAHours)
+	if err != nil {
+		return fmt.Errorf("failed to save team settings: %w", err)
+	}
+	return nil
+}
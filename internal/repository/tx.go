@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+)
+
+const uniqueViolation = "23505"
+
+type queryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+type txState struct {
+	tx         *sql.Tx
+	dialect    dialect
+	savepoints int
+}
+
+type TxManager struct {
+	db *sql.DB
+}
+
+func NewTxManager(db *sql.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+func (m *TxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	txCtx, tx, err := beginTx(ctx, m.db)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err = fn(txCtx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+type scopedTx struct {
+	queryer
+	tx        *sql.Tx
+	ctx       context.Context
+	savepoint string
+	done      bool
+}
+
+func beginTx(ctx context.Context, db *sql.DB) (context.Context, *scopedTx, error) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.savepoints++
+		savepoint := fmt.Sprintf("sp_%d", state.savepoints)
+		if _, err := state.tx.ExecContext(ctx, "SAVEPOINT "+savepoint); err != nil {
+			return nil, nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		return ctx, &scopedTx{queryer: state.dialect.bind(state.tx), tx: state.tx, ctx: ctx, savepoint: savepoint}, nil
+	}
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	d := dialectOf(db)
+	return context.WithValue(ctx, txKey{}, &txState{tx: tx, dialect: d}), &scopedTx{queryer: d.bind(tx), tx: tx, ctx: ctx}, nil
+}
+
+func (t *scopedTx) Commit() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+
+	if t.savepoint == "" {
+		return t.tx.Commit()
+	}
+	_, err := t.tx.ExecContext(t.ctx, "RELEASE SAVEPOINT "+t.savepoint)
+	return err
+}
+
+func (t *scopedTx) Rollback() error {
+	if t.done {
+		return sql.ErrTxDone
+	}
+	t.done = true
+
+	if t.savepoint == "" {
+		return t.tx.Rollback()
+	}
+	_, err := t.tx.ExecContext(context.WithoutCancel(t.ctx), "ROLLBACK TO SAVEPOINT "+t.savepoint)
+	return err
+}
+
+func conn(ctx context.Context, db *sql.DB) queryer {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.dialect.bind(state.tx)
+	}
+	return dialectOf(db).bind(db)
+}
+
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == uniqueViolation
+	}
+	return isSQLiteUniqueViolation(err)
+}
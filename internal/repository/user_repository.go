@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -15,21 +16,23 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
-func (r *UserRepository) GetUser(userID string) (*models.User, error) {
+func (r *UserRepository) GetUser(ctx context.Context, userID string) (*models.User, error) {
 	var user models.User
-	err := r.db.QueryRow(`
-		SELECT user_id, username, team_name, is_active
+	var maxOpenReviews sql.NullInt64
+	err := conn(ctx, r.db).QueryRowContext(ctx, `
+		SELECT user_id, username, team_name, is_active, max_open_reviews
 		FROM users
 		WHERE user_id = $1
-	`, userID).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive)
+	`, userID).Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &maxOpenReviews)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
+	user.MaxOpenReviews = nullableInt(maxOpenReviews)
 	return &user, nil
 }
 
-func (r *UserRepository) SetIsActive(userID string, isActive bool) (*models.User, error) {
-	_, err := r.db.Exec(`
+func (r *UserRepository) SetIsActive(ctx context.Context, userID string, isActive bool) (*models.User, error) {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
 		UPDATE users
 		SET is_active = $1, updated_at = CURRENT_TIMESTAMP
 		WHERE user_id = $2
@@ -38,17 +41,35 @@ func (r *UserRepository) SetIsActive(userID string, isActive bool) (*models.User
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 
-	return r.GetUser(userID)
+	return r.GetUser(ctx, userID)
 }
 
-func (r *UserRepository) GetActiveTeamMembers(teamName string, excludeUserID string) ([]models.User, error) {
+func (r *UserRepository) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (*models.User, error) {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
+		UPDATE users
+		SET max_open_reviews = $1, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $2
+	`, maxOpenReviews, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update user capacity: %w", err)
+	}
+
+	return r.GetUser(ctx, userID)
+}
+
+func (r *UserRepository) GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]models.User, error) {
 	query := `
-		SELECT user_id, username, team_name, is_active
+		SELECT user_id, username, team_name, is_active, max_open_reviews
 		FROM users
 		WHERE team_name = $1 AND is_active = true AND user_id != $2
+			AND NOT EXISTS (
+				SELECT 1 FROM user_absences a
+				WHERE a.user_id = users.user_id
+					AND a.starts_at <= CURRENT_TIMESTAMP AND a.ends_at > CURRENT_TIMESTAMP
+			)
 		ORDER BY user_id
 	`
-	rows, err := r.db.Query(query, teamName, excludeUserID)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamName, excludeUserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get active team members: %w", err)
 	}
@@ -57,20 +78,33 @@ func (r *UserRepository) GetActiveTeamMembers(teamName string, excludeUserID str
 	var users []models.User
 	for rows.Next() {
 		var user models.User
-		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive); err != nil {
+		var maxOpenReviews sql.NullInt64
+		if err := rows.Scan(&user.UserID, &user.Username, &user.TeamName, &user.IsActive, &maxOpenReviews); err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
 		}
+		user.MaxOpenReviews = nullableInt(maxOpenReviews)
 		users = append(users, user)
 	}
 
 	return users, rows.Err()
 }
 
-func (r *UserRepository) BulkDeactivateTeamMembers(teamName string) error {
-	_, err := r.db.Exec(`
+func (r *UserRepository) DeactivateTeamMembers(ctx context.Context, teamName string) error {
+	_, err := conn(ctx, r.db).ExecContext(ctx, `
 		UPDATE users
 		SET is_active = false, updated_at = CURRENT_TIMESTAMP
 		WHERE team_name = $1
 	`, teamName)
-	return err
+	if err != nil {
+		return fmt.Errorf("failed to deactivate team members: %w", err)
+	}
+	return nil
+}
+
+func nullableInt(value sql.NullInt64) *int {
+	if !value.Valid {
+		return nil
+	}
+	v := int(value.Int64)
+	return &v
 }
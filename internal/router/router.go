@@ -13,10 +13,12 @@ import (
 func SetupRouter(
 	teamHandler *handler.TeamHandler,
 	userHandler *handler.UserHandler,
+	absenceHandler *handler.AbsenceHandler,
 	prHandler *handler.PRHandler,
 	statsHandler *handler.StatsHandler,
 	healthHandler *handler.HealthHandler,
 	metricsHandler *handler.MetricsHandler,
+	idempotency gin.HandlerFunc,
 ) *gin.Engine {
 	r := gin.New()
 
@@ -24,6 +26,9 @@ func SetupRouter(
 	r.Use(middleware.RequestID())
 	r.Use(middleware.Logger())
 	r.Use(middleware.PrometheusMetrics())
+	if idempotency != nil {
+		r.Use(idempotency)
+	}
 
 	r.GET("/health", healthHandler.HealthCheck)
 	r.GET("/metrics", metricsHandler.Metrics)
@@ -44,19 +49,34 @@ func SetupRouter(
 		teams.POST("/add", teamHandler.AddTeam)
 		teams.GET("/get", teamHandler.GetTeam)
 		teams.POST("/bulkDeactivate", teamHandler.BulkDeactivateTeam)
+		teams.GET("/settings", teamHandler.GetTeamSettings)
+		teams.POST("/settings", teamHandler.UpdateTeamSettings)
 	}
 
 	users := r.Group("/users")
 	{
 		users.POST("/setIsActive", userHandler.SetIsActive)
+		users.POST("/setMaxOpenReviews", userHandler.SetMaxOpenReviews)
 		users.GET("/getReview", userHandler.GetReview)
+		users.GET("/absences", absenceHandler.GetAbsences)
+		users.POST("/absences", absenceHandler.CreateAbsence)
+		users.DELETE("/absences", absenceHandler.DeleteAbsence)
 	}
 
 	prs := r.Group("/pullRequest")
 	{
 		prs.POST("/create", prHandler.CreatePR)
 		prs.POST("/merge", prHandler.MergePR)
+		prs.POST("/ready", prHandler.MarkReady)
+		prs.POST("/close", prHandler.ClosePR)
+		prs.POST("/reopen", prHandler.ReopenPR)
 		prs.POST("/reassign", prHandler.ReassignReviewer)
+		prs.POST("/addReviewer", prHandler.AddReviewer)
+		prs.POST("/removeReviewer", prHandler.RemoveReviewer)
+		prs.POST("/decline", prHandler.DeclineReview)
+		prs.POST("/review", prHandler.SubmitReview)
+		prs.GET("/get", prHandler.GetPR)
+		prs.GET("/list", prHandler.ListPRs)
 	}
 
 	r.GET("/stats", statsHandler.GetStats)
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	domainerrors "github.com/avito/pr-reviewer-service/internal/domain/errors"
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+type AbsenceService struct {
+	absenceRepo AbsenceRepositoryInterface
+	userRepo    UserRepositoryInterface
+}
+
+func NewAbsenceService(absenceRepo AbsenceRepositoryInterface, userRepo UserRepositoryInterface) *AbsenceService {
+	return &AbsenceService{absenceRepo: absenceRepo, userRepo: userRepo}
+}
+
+func (s *AbsenceService) CreateAbsence(ctx context.Context, absence *models.Absence) (*models.Absence, error) {
+	if !absence.EndsAt.After(absence.StartsAt) {
+		return nil, fmt.Errorf("create absence: %w", domainerrors.ErrInvalidAbsenceWindow)
+	}
+
+	if err := s.ensureUserExists(ctx, absence.UserID); err != nil {
+		return nil, err
+	}
+
+	if err := s.absenceRepo.CreateAbsence(ctx, absence); err != nil {
+		return nil, fmt.Errorf("failed to create absence: %w", err)
+	}
+	return absence, nil
+}
+
+func (s *AbsenceService) GetAbsences(ctx context.Context, userID string) ([]models.Absence, error) {
+	if err := s.ensureUserExists(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.absenceRepo.GetAbsencesByUser(ctx, userID)
+}
+
+func (s *AbsenceService) DeleteAbsence(ctx context.Context, absenceID int64) error {
+	if err := s.absenceRepo.DeleteAbsence(ctx, absenceID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("delete absence: %w", domainerrors.ErrAbsenceNotFound)
+		}
+		return fmt.Errorf("failed to delete absence: %w", err)
+	}
+	return nil
+}
+
+func (s *AbsenceService) ensureUserExists(ctx context.Context, userID string) error {
+	if _, err := s.userRepo.GetUser(ctx, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("ensure user exists: %w", domainerrors.ErrUserNotFound)
+		}
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+func evaluateMergePolicy(pr *models.PullRequest, settings *models.TeamSettings) []string {
+	var approvals int
+	var changesRequested, awaiting []string
+	for _, reviewer := range pr.Reviewers {
+		switch reviewer.State {
+		case models.ReviewApproved:
+			approvals++
+		case models.ReviewChangesRequested:
+			changesRequested = append(changesRequested, reviewer.ReviewerID)
+			awaiting = append(awaiting, reviewer.ReviewerID)
+		default:
+			awaiting = append(awaiting, reviewer.ReviewerID)
+		}
+	}
+
+	var unmet []string
+	if approvals < settings.MinApprovals {
+		unmet = append(unmet, fmt.Sprintf("requires at least %d approvals, has %d", settings.MinApprovals, approvals))
+	}
+	if settings.BlockOnChangesRequested && len(changesRequested) > 0 {
+		unmet = append(unmet, fmt.Sprintf("changes requested by %s", strings.Join(changesRequested, ", ")))
+	}
+	if settings.RequireAllApproved && len(awaiting) > 0 {
+		unmet = append(unmet, fmt.Sprintf("awaiting approval from %s", strings.Join(awaiting, ", ")))
+	}
+	return unmet
+}
@@ -1,30 +1,54 @@
 package service
 
 import (
+	"context"
+
 	"github.com/avito/pr-reviewer-service/internal/models"
 )
 
 type PRRepositoryInterface interface {
-	PRExists(prID string) (bool, error)
-	CreatePR(pr *models.PullRequest) error
-	GetPR(prID string) (*models.PullRequest, error)
-	MergePR(prID string) (*models.PullRequest, error)
-	ReassignReviewer(prID, oldReviewerID, newReviewerID string) error
-	GetPRsByReviewer(reviewerID string) ([]models.PullRequestShort, error)
-	GetUserStats() ([]models.UserStat, error)
-	GetPRStats() (*models.PRStat, error)
+	CreatePR(ctx context.Context, pr *models.PullRequest, selectReviewers func(ctx context.Context) ([]string, error)) error
+	GetPR(ctx context.Context, prID string) (*models.PullRequest, error)
+	GetPRForUpdate(ctx context.Context, prID string) (*models.PullRequest, error)
+	MergePR(ctx context.Context, prID string, check func(ctx context.Context, pr *models.PullRequest) error) (*models.PullRequest, error)
+	TransitionPR(ctx context.Context, prID string, from, to models.PullRequestStatus, reviewerIDs []string) error
+	ReassignReviewer(ctx context.Context, prID, oldReviewerID string, pick func(ctx context.Context, pr *models.PullRequest) (string, error)) (string, error)
+	AddReviewer(ctx context.Context, prID, reviewerID string, check func(ctx context.Context, pr *models.PullRequest) error) error
+	RemoveReviewer(ctx context.Context, prID, reviewerID string, check func(ctx context.Context, pr *models.PullRequest) error) error
+	DeclineReview(ctx context.Context, prID, reviewerID string, category models.DeclineCategory, reason string, pick func(ctx context.Context, pr *models.PullRequest) (string, error)) (string, error)
+	ApplyReassignments(ctx context.Context, reassignments []models.ReviewerReassignment, reason models.ReviewerChangeReason) error
+	ListPRs(ctx context.Context, filter *models.PRListFilter) ([]models.PullRequest, string, error)
+	GetReviewerChanges(ctx context.Context, prID string) ([]models.ReviewerChange, error)
+	SetReviewState(ctx context.Context, prID, reviewerID string, state models.ReviewState) error
+	GetPRsByReviewer(ctx context.Context, reviewerID string, filter *models.ReviewListFilter) ([]models.PullRequestShort, string, error)
+	GetOpenReviewCounts(ctx context.Context, userIDs []string) (map[string]int, error)
+	GetOpenPRIDsByReviewerTeam(ctx context.Context, teamName string) ([]string, error)
+	GetUserStats(ctx context.Context) ([]models.UserStat, error)
+	GetPRStats(ctx context.Context) (*models.PRStat, error)
 }
 
 type UserRepositoryInterface interface {
-	GetUser(userID string) (*models.User, error)
-	SetIsActive(userID string, isActive bool) (*models.User, error)
-	GetActiveTeamMembers(teamName string, excludeUserID string) ([]models.User, error)
-	BulkDeactivateTeamMembers(teamName string) error
+	GetUser(ctx context.Context, userID string) (*models.User, error)
+	SetIsActive(ctx context.Context, userID string, isActive bool) (*models.User, error)
+	SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (*models.User, error)
+	GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]models.User, error)
+	DeactivateTeamMembers(ctx context.Context, teamName string) error
 }
 
 type TeamRepositoryInterface interface {
-	TeamExists(teamName string) (bool, error)
-	CreateTeam(team *models.Team) error
-	GetTeam(teamName string) (*models.Team, error)
+	TeamExists(ctx context.Context, teamName string) (bool, error)
+	CreateTeam(ctx context.Context, team *models.Team) error
+	GetTeam(ctx context.Context, teamName string) (*models.Team, error)
+	GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error)
+	UpsertTeamSettings(ctx context.Context, settings *models.TeamSettings) error
+}
+
+type AbsenceRepositoryInterface interface {
+	CreateAbsence(ctx context.Context, absence *models.Absence) error
+	GetAbsencesByUser(ctx context.Context, userID string) ([]models.Absence, error)
+	DeleteAbsence(ctx context.Context, absenceID int64) error
 }
 
+type TxManagerInterface interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
@@ -1,167 +1,716 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
-	"math/rand"
-	"time"
+	"slices"
+	"strings"
 
+	domainerrors "github.com/avito/pr-reviewer-service/internal/domain/errors"
 	"github.com/avito/pr-reviewer-service/internal/models"
 	"github.com/avito/pr-reviewer-service/internal/repository"
 )
 
+const defaultPageLimit = 50
+
 type PRService struct {
-	prRepo   PRRepositoryInterface
-	userRepo UserRepositoryInterface
+	prRepo    PRRepositoryInterface
+	userRepo  UserRepositoryInterface
+	teamRepo  TeamRepositoryInterface
+	selectors *ReviewerSelectors
 }
 
-func NewPRService(prRepo *repository.PullRequestRepository, userRepo *repository.UserRepository) *PRService {
-	return &PRService{prRepo: prRepo, userRepo: userRepo}
+func NewPRService(
+	prRepo PRRepositoryInterface,
+	userRepo UserRepositoryInterface,
+	teamRepo TeamRepositoryInterface,
+	selectors *ReviewerSelectors,
+) *PRService {
+	if selectors == nil {
+		selectors = NewReviewerSelectors(StrategyLeastLoaded, nil)
+	}
+	return &PRService{prRepo: prRepo, userRepo: userRepo, teamRepo: teamRepo, selectors: selectors}
 }
 
-func (s *PRService) CreatePR(prID, prName, authorID string) (*models.PullRequest, error) {
-	exists, err := s.prRepo.PRExists(prID)
+func (s *PRService) CreatePR(ctx context.Context, prID, prName, authorID string, draft bool) (*models.PullRequest, error) {
+	author, err := s.userRepo.GetUser(ctx, authorID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to check PR existence: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("create PR: %w", domainerrors.ErrAuthorNotFound)
+		}
+		return nil, fmt.Errorf("failed to get author: %w", err)
 	}
-	if exists {
-		return nil, fmt.Errorf("PR already exists")
+
+	pr := &models.PullRequest{
+		PullRequestID:   prID,
+		PullRequestName: prName,
+		AuthorID:        authorID,
+		Status:          models.StatusOpen,
 	}
 
-	author, err := s.userRepo.GetUser(authorID)
+	var selectReviewers func(ctx context.Context) ([]string, error)
+	if draft {
+		pr.Status = models.StatusDraft
+	} else {
+		selectReviewers = func(ctx context.Context) ([]string, error) {
+			return s.selectInitialReviewers(ctx, author, nil)
+		}
+	}
+
+	if err := s.prRepo.CreatePR(ctx, pr, selectReviewers); err != nil {
+		if errors.Is(err, repository.ErrPRAlreadyExists) {
+			return nil, fmt.Errorf("create PR: %w", domainerrors.ErrPRExists)
+		}
+		return nil, err
+	}
+
+	return s.prRepo.GetPR(ctx, prID)
+}
+
+func (s *PRService) MergePR(ctx context.Context, prID string, force bool) (*models.PullRequest, error) {
+	pr, err := s.prRepo.MergePR(ctx, prID, func(ctx context.Context, pr *models.PullRequest) error {
+		if err := validateTransition(pr.Status, models.StatusMerged); err != nil {
+			return err
+		}
+		if force {
+			return nil
+		}
+
+		author, err := s.userRepo.GetUser(ctx, pr.AuthorID)
+		if err != nil {
+			return fmt.Errorf("failed to get author: %w", err)
+		}
+
+		settings, err := loadTeamSettings(ctx, s.teamRepo, author.TeamName)
+		if err != nil {
+			return err
+		}
+
+		if unmet := evaluateMergePolicy(pr, settings); len(unmet) > 0 {
+			return fmt.Errorf("merge PR: %w", domainerrors.ErrMergeBlocked.WithDetails(unmet))
+		}
+		return nil
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("author not found")
+			return nil, fmt.Errorf("merge PR: %w", domainerrors.ErrPRNotFound)
 		}
-		return nil, fmt.Errorf("failed to get author: %w", err)
+		return nil, err
 	}
 
-	candidates, err := s.userRepo.GetActiveTeamMembers(author.TeamName, authorID)
+	return pr, nil
+}
+
+func (s *PRService) MarkReady(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return s.transition(ctx, prID, models.StatusOpen)
+}
+
+func (s *PRService) ClosePR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return s.transition(ctx, prID, models.StatusClosed)
+}
+
+func (s *PRService) ReopenPR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	return s.transition(ctx, prID, models.StatusReopened)
+}
+
+func (s *PRService) transition(ctx context.Context, prID string, to models.PullRequestStatus) (*models.PullRequest, error) {
+	pr, err := s.GetPR(ctx, prID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get team members: %w", err)
+		return nil, err
 	}
 
-	maxReviewers := 2
-	if len(candidates) < maxReviewers {
-		maxReviewers = len(candidates)
+	if err := validateTransition(pr.Status, to); err != nil {
+		return nil, err
 	}
 
 	var reviewerIDs []string
-	if maxReviewers > 0 {
-		r := rand.New(rand.NewSource(time.Now().UnixNano()))
-		selected := r.Perm(len(candidates))[:maxReviewers]
-		for _, idx := range selected {
-			reviewerIDs = append(reviewerIDs, candidates[idx].UserID)
+	if to.IsOpen() && len(pr.AssignedReviewers) == 0 {
+		author, err := s.userRepo.GetUser(ctx, pr.AuthorID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get author: %w", err)
+		}
+
+		reviewerIDs, err = s.selectInitialReviewers(ctx, author, pr.DeclinedReviewers)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	pr := &models.PullRequest{
-		PullRequestID:     prID,
-		PullRequestName:   prName,
-		AuthorID:          authorID,
-		Status:            models.StatusOpen,
-		AssignedReviewers: reviewerIDs,
+	if err := s.prRepo.TransitionPR(ctx, prID, pr.Status, to, reviewerIDs); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, invalidTransition(pr.Status, to)
+		}
+		return nil, fmt.Errorf("failed to update PR status: %w", err)
 	}
 
-	if err := s.prRepo.CreatePR(pr); err != nil {
-		return nil, fmt.Errorf("failed to create PR: %w", err)
+	return s.prRepo.GetPR(ctx, prID)
+}
+
+func (s *PRService) selectInitialReviewers(ctx context.Context, author *models.User, excludeIDs []string) ([]string, error) {
+	members, err := s.userRepo.GetActiveTeamMembers(ctx, author.TeamName, author.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
 	}
 
-	return s.prRepo.GetPR(prID)
+	candidates := replacementCandidates(&models.PullRequest{AuthorID: author.UserID, DeclinedReviewers: excludeIDs}, members)
+
+	settings, err := loadTeamSettings(ctx, s.teamRepo, author.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.selectReviewers(ctx, settings, candidates, settings.ReviewerCount, nil)
 }
 
-func (s *PRService) MergePR(prID string) (*models.PullRequest, error) {
-	pr, err := s.prRepo.GetPR(prID)
+func (s *PRService) GetPR(ctx context.Context, prID string) (*models.PullRequest, error) {
+	pr, err := s.prRepo.GetPR(ctx, prID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("PR not found")
+			return nil, fmt.Errorf("get PR: %w", domainerrors.ErrPRNotFound)
 		}
 		return nil, fmt.Errorf("failed to get PR: %w", err)
 	}
+	return pr, nil
+}
+
+func (s *PRService) GetPRDetails(ctx context.Context, prID string) (*models.PullRequest, error) {
+	pr, err := s.GetPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
+
+	pr.ReassignmentHistory, err = s.prRepo.GetReviewerChanges(ctx, prID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reassignment history: %w", err)
+	}
+	return pr, nil
+}
+
+func (s *PRService) SubmitReview(ctx context.Context, prID, reviewerID string, state models.ReviewState) (*models.PullRequest, error) {
+	pr, err := s.GetPR(ctx, prID)
+	if err != nil {
+		return nil, err
+	}
 
 	if pr.Status == models.StatusMerged {
-		return pr, nil
+		return nil, fmt.Errorf("submit review: %w", domainerrors.ErrReviewMerged)
+	}
+	if !pr.Status.IsOpen() {
+		return nil, fmt.Errorf("submit review: %w", domainerrors.ErrPRNotOpen)
+	}
+
+	if err := s.prRepo.SetReviewState(ctx, prID, reviewerID, state); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("submit review: %w", domainerrors.ErrNotAssigned)
+		}
+		return nil, fmt.Errorf("failed to submit review: %w", err)
+	}
+
+	return s.prRepo.GetPR(ctx, prID)
+}
+
+func (s *PRService) ReassignReviewer(ctx context.Context, prID, oldReviewerID, newReviewerID string) (string, *models.PullRequest, error) {
+	replacedBy, err := s.prRepo.ReassignReviewer(ctx, prID, oldReviewerID, func(ctx context.Context, pr *models.PullRequest) (string, error) {
+		if pr.Status == models.StatusMerged {
+			return "", fmt.Errorf("reassign reviewer: %w", domainerrors.ErrPRMerged)
+		}
+		if !pr.Status.IsOpen() {
+			return "", fmt.Errorf("reassign reviewer: %w", domainerrors.ErrPRNotOpen)
+		}
+		if !slices.Contains(pr.AssignedReviewers, oldReviewerID) {
+			return "", fmt.Errorf("reassign reviewer: %w", domainerrors.ErrNotAssigned)
+		}
+
+		oldReviewer, err := s.userRepo.GetUser(ctx, oldReviewerID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get old reviewer: %w", err)
+		}
+
+		if newReviewerID != "" {
+			return newReviewerID, s.validateChosenReviewer(ctx, pr, oldReviewer, newReviewerID)
+		}
+		return s.pickReplacement(ctx, pr, oldReviewer)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil, fmt.Errorf("reassign reviewer: %w", domainerrors.ErrPRNotFound)
+		}
+		if errors.Is(err, repository.ErrReviewerNotAssigned) {
+			return "", nil, fmt.Errorf("reassign reviewer: %w", domainerrors.ErrNotAssigned)
+		}
+		return "", nil, err
+	}
+
+	updatedPR, err := s.prRepo.GetPR(ctx, prID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get updated PR: %w", err)
+	}
+
+	return replacedBy, updatedPR, nil
+}
+
+func (s *PRService) validateChosenReviewer(ctx context.Context, pr *models.PullRequest, oldReviewer *models.User, newReviewerID string) error {
+	newReviewer, err := s.userRepo.GetUser(ctx, newReviewerID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("validate chosen reviewer: %w", domainerrors.ErrUserNotFound)
+		}
+		return fmt.Errorf("failed to get new reviewer: %w", err)
+	}
+
+	if newReviewer.TeamName != oldReviewer.TeamName {
+		return fmt.Errorf("validate chosen reviewer: %w", domainerrors.ErrWrongTeam)
+	}
+	return s.validateManualReviewer(ctx, pr, newReviewer)
+}
+
+func (s *PRService) pickReplacement(ctx context.Context, pr *models.PullRequest, oldReviewer *models.User) (string, error) {
+	candidates, err := s.userRepo.GetActiveTeamMembers(ctx, oldReviewer.TeamName, oldReviewer.UserID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get team members: %w", err)
+	}
+
+	available := replacementCandidates(pr, candidates)
+	if len(available) == 0 {
+		return "", fmt.Errorf("pick replacement: %w", domainerrors.ErrNoCandidate)
+	}
+
+	settings, err := loadTeamSettings(ctx, s.teamRepo, oldReviewer.TeamName)
+	if err != nil {
+		return "", err
+	}
+
+	selected, err := s.selectReviewers(ctx, settings, available, 1, nil)
+	if err != nil {
+		return "", err
+	}
+	return selected[0], nil
+}
+
+func (s *PRService) AddReviewer(ctx context.Context, prID, reviewerID string) (*models.PullRequest, error) {
+	err := s.prRepo.AddReviewer(ctx, prID, reviewerID, func(ctx context.Context, pr *models.PullRequest) error {
+		if err := checkEditable(pr); err != nil {
+			return fmt.Errorf("add reviewer: %w", err)
+		}
+
+		reviewer, err := s.userRepo.GetUser(ctx, reviewerID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("add reviewer: %w", domainerrors.ErrUserNotFound)
+			}
+			return fmt.Errorf("failed to get reviewer: %w", err)
+		}
+		return s.validateManualReviewer(ctx, pr, reviewer)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("add reviewer: %w", domainerrors.ErrPRNotFound)
+		}
+		if errors.Is(err, repository.ErrReviewerAlreadyAssigned) {
+			return nil, fmt.Errorf("add reviewer: %w", domainerrors.ErrAlreadyAssigned)
+		}
+		return nil, fmt.Errorf("failed to add reviewer: %w", err)
+	}
+
+	return s.prRepo.GetPR(ctx, prID)
+}
+
+func (s *PRService) RemoveReviewer(ctx context.Context, prID, reviewerID string) (*models.PullRequest, error) {
+	err := s.prRepo.RemoveReviewer(ctx, prID, reviewerID, func(_ context.Context, pr *models.PullRequest) error {
+		if err := checkEditable(pr); err != nil {
+			return fmt.Errorf("remove reviewer: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("remove reviewer: %w", domainerrors.ErrPRNotFound)
+		}
+		if errors.Is(err, repository.ErrReviewerNotAssigned) {
+			return nil, fmt.Errorf("remove reviewer: %w", domainerrors.ErrNotAssigned)
+		}
+		return nil, fmt.Errorf("failed to remove reviewer: %w", err)
 	}
 
-	return s.prRepo.MergePR(prID)
+	return s.prRepo.GetPR(ctx, prID)
 }
 
-func (s *PRService) ReassignReviewer(prID, oldReviewerID string) (string, *models.PullRequest, error) {
-	pr, err := s.prRepo.GetPR(prID)
+func (s *PRService) DeclineReview(ctx context.Context, prID, reviewerID string, category models.DeclineCategory, reason string) (string, *models.PullRequest, error) {
+	if category == "" {
+		category = models.DeclineOther
+	}
+
+	newReviewerID, err := s.prRepo.DeclineReview(ctx, prID, reviewerID, category, reason, func(ctx context.Context, pr *models.PullRequest) (string, error) {
+		if err := checkEditable(pr); err != nil {
+			return "", fmt.Errorf("decline review: %w", err)
+		}
+		if !slices.Contains(pr.AssignedReviewers, reviewerID) {
+			return "", fmt.Errorf("decline review: %w", domainerrors.ErrNotAssigned)
+		}
+
+		reviewer, err := s.userRepo.GetUser(ctx, reviewerID)
+		if err != nil {
+			return "", fmt.Errorf("failed to get reviewer: %w", err)
+		}
+
+		pr.DeclinedReviewers = append(pr.DeclinedReviewers, reviewerID)
+		newReviewerID, err := s.pickReplacement(ctx, pr, reviewer)
+		if errors.Is(err, domainerrors.ErrNoCandidate) || errors.Is(err, domainerrors.ErrCapacityExhausted) {
+			return "", nil
+		}
+		return newReviewerID, err
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return "", nil, fmt.Errorf("PR not found")
+			return "", nil, fmt.Errorf("decline review: %w", domainerrors.ErrPRNotFound)
 		}
-		return "", nil, fmt.Errorf("failed to get PR: %w", err)
+		if errors.Is(err, repository.ErrReviewerNotAssigned) {
+			return "", nil, fmt.Errorf("decline review: %w", domainerrors.ErrNotAssigned)
+		}
+		return "", nil, fmt.Errorf("failed to decline review: %w", err)
+	}
+
+	updatedPR, err := s.prRepo.GetPR(ctx, prID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get updated PR: %w", err)
 	}
 
+	return newReviewerID, updatedPR, nil
+}
+
+func checkEditable(pr *models.PullRequest) error {
 	if pr.Status == models.StatusMerged {
-		return "", nil, fmt.Errorf("cannot reassign on merged PR")
+		return domainerrors.ErrPRMerged
 	}
+	if !pr.Status.IsOpen() {
+		return domainerrors.ErrPRNotOpen
+	}
+	return nil
+}
 
-	found := false
+func (s *PRService) validateManualReviewer(ctx context.Context, pr *models.PullRequest, reviewer *models.User) error {
+	if reviewer.UserID == pr.AuthorID {
+		return fmt.Errorf("validate manual reviewer: %w", domainerrors.ErrAuthorCannotReview)
+	}
+	if !reviewer.IsActive {
+		return fmt.Errorf("validate manual reviewer: %w", domainerrors.ErrReviewerInactive)
+	}
 	for _, reviewerID := range pr.AssignedReviewers {
-		if reviewerID == oldReviewerID {
-			found = true
+		if reviewerID == reviewer.UserID {
+			return fmt.Errorf("validate manual reviewer: %w", domainerrors.ErrAlreadyAssigned)
+		}
+	}
+
+	available, err := s.userRepo.GetActiveTeamMembers(ctx, reviewer.TeamName, "")
+	if err != nil {
+		return fmt.Errorf("failed to get team members: %w", err)
+	}
+	isAvailable := false
+	for _, member := range available {
+		if member.UserID == reviewer.UserID {
+			isAvailable = true
 			break
 		}
 	}
+	if !isAvailable {
+		return fmt.Errorf("validate manual reviewer: %w", domainerrors.ErrReviewerUnavailable)
+	}
 
-	if !found {
-		return "", nil, fmt.Errorf("reviewer is not assigned to this PR")
+	if reviewer.MaxOpenReviews != nil {
+		loads, err := s.prRepo.GetOpenReviewCounts(ctx, []string{reviewer.UserID})
+		if err != nil {
+			return fmt.Errorf("failed to get open review counts: %w", err)
+		}
+		if loads[reviewer.UserID] >= *reviewer.MaxOpenReviews {
+			return fmt.Errorf("validate manual reviewer: %w", domainerrors.ErrReviewerAtCapacity)
+		}
+	}
+	return nil
+}
+
+func (s *PRService) PlanReviewerReplacements(ctx context.Context, reviewer *models.User) (*models.ReassignmentReport, error) {
+	report := &models.ReassignmentReport{
+		Reassigned:  []models.ReviewerReassignment{},
+		NoCandidate: []models.ReviewerReassignment{},
 	}
 
-	oldReviewer, err := s.userRepo.GetUser(oldReviewerID)
+	prs, _, err := s.prRepo.GetPRsByReviewer(ctx, reviewer.UserID, &models.ReviewListFilter{
+		Statuses: []models.PullRequestStatus{models.StatusOpen, models.StatusReopened},
+	})
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to get old reviewer: %w", err)
+		return nil, fmt.Errorf("failed to get reviewer PRs: %w", err)
+	}
+
+	candidates, err := s.userRepo.GetActiveTeamMembers(ctx, reviewer.TeamName, reviewer.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+
+	settings, err := loadTeamSettings(ctx, s.teamRepo, reviewer.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	planned := make(map[string]int)
+	for _, short := range prs {
+		if !short.Status.IsOpen() {
+			continue
+		}
+
+		pr, err := s.prRepo.GetPRForUpdate(ctx, short.PullRequestID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get PR: %w", err)
+		}
+
+		reassignment := models.ReviewerReassignment{
+			PullRequestID: pr.PullRequestID,
+			OldReviewerID: reviewer.UserID,
+		}
+
+		selected, err := s.selectReviewers(ctx, settings, replacementCandidates(pr, candidates), 1, planned)
+		if err != nil && !errors.Is(err, domainerrors.ErrCapacityExhausted) {
+			return nil, err
+		}
+		if len(selected) == 0 {
+			report.NoCandidate = append(report.NoCandidate, reassignment)
+			continue
+		}
+
+		reassignment.NewReviewerID = selected[0]
+		planned[selected[0]]++
+		report.Reassigned = append(report.Reassigned, reassignment)
+	}
+
+	return report, nil
+}
+
+func (s *PRService) ApplyReassignments(ctx context.Context, reassignments []models.ReviewerReassignment, reason models.ReviewerChangeReason) error {
+	if len(reassignments) == 0 {
+		return nil
+	}
+	if err := s.prRepo.ApplyReassignments(ctx, reassignments, reason); err != nil {
+		if errors.Is(err, repository.ErrReviewerNotAssigned) {
+			return fmt.Errorf("apply reassignments: %w", domainerrors.ErrNotAssigned)
+		}
+		return fmt.Errorf("failed to apply reassignments: %w", err)
+	}
+	return nil
+}
+
+func (s *PRService) PlanTeamDeactivation(ctx context.Context, teamName string, memberIDs []string) ([]models.PRReassignmentReport, error) {
+	deactivating := make(map[string]bool, len(memberIDs))
+	for _, memberID := range memberIDs {
+		deactivating[memberID] = true
 	}
 
-	candidates, err := s.userRepo.GetActiveTeamMembers(oldReviewer.TeamName, oldReviewerID)
+	prIDs, err := s.prRepo.GetOpenPRIDsByReviewerTeam(ctx, teamName)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to get team members: %w", err)
+		return nil, fmt.Errorf("failed to get open PRs: %w", err)
+	}
+
+	teamMembers := make(map[string][]models.User)
+	teamSettings := make(map[string]*models.TeamSettings)
+	planned := make(map[string]int)
+	reports := make([]models.PRReassignmentReport, 0, len(prIDs))
+
+	for _, prID := range prIDs {
+		pr, getErr := s.prRepo.GetPRForUpdate(ctx, prID)
+		if getErr != nil {
+			return nil, fmt.Errorf("failed to get PR: %w", getErr)
+		}
+
+		author, getErr := s.userRepo.GetUser(ctx, pr.AuthorID)
+		if getErr != nil {
+			return nil, fmt.Errorf("failed to get author: %w", getErr)
+		}
+
+		if _, ok := teamMembers[author.TeamName]; !ok {
+			members, membersErr := s.userRepo.GetActiveTeamMembers(ctx, author.TeamName, "")
+			if membersErr != nil {
+				return nil, fmt.Errorf("failed to get team members: %w", membersErr)
+			}
+			var remaining []models.User
+			for _, member := range members {
+				if !deactivating[member.UserID] {
+					remaining = append(remaining, member)
+				}
+			}
+			teamMembers[author.TeamName] = remaining
+
+			settings, settingsErr := loadTeamSettings(ctx, s.teamRepo, author.TeamName)
+			if settingsErr != nil {
+				return nil, settingsErr
+			}
+			teamSettings[author.TeamName] = settings
+		}
+
+		report := models.PRReassignmentReport{
+			PullRequestID: pr.PullRequestID,
+			AuthorID:      pr.AuthorID,
+			Reassigned:    []models.ReviewerReassignment{},
+			NoCandidate:   []models.ReviewerReassignment{},
+		}
+
+		for _, reviewerID := range append([]string(nil), pr.AssignedReviewers...) {
+			if !deactivating[reviewerID] {
+				continue
+			}
+
+			reassignment := models.ReviewerReassignment{
+				PullRequestID: pr.PullRequestID,
+				OldReviewerID: reviewerID,
+			}
+
+			available := replacementCandidates(pr, teamMembers[author.TeamName])
+			selected, selectErr := s.selectReviewers(ctx, teamSettings[author.TeamName], available, 1, planned)
+			if selectErr != nil && !errors.Is(selectErr, domainerrors.ErrCapacityExhausted) {
+				return nil, selectErr
+			}
+			if len(selected) == 0 {
+				report.NoCandidate = append(report.NoCandidate, reassignment)
+				continue
+			}
+
+			reassignment.NewReviewerID = selected[0]
+			planned[selected[0]]++
+			pr.AssignedReviewers = append(pr.AssignedReviewers, selected[0])
+			report.Reassigned = append(report.Reassigned, reassignment)
+		}
+
+		reports = append(reports, report)
 	}
 
+	return reports, nil
+}
+
+func replacementCandidates(pr *models.PullRequest, members []models.User) []models.User {
 	excludeIDs := make(map[string]bool)
 	for _, reviewerID := range pr.AssignedReviewers {
 		excludeIDs[reviewerID] = true
 	}
+	for _, reviewerID := range pr.DeclinedReviewers {
+		excludeIDs[reviewerID] = true
+	}
 	excludeIDs[pr.AuthorID] = true
 
 	var available []models.User
-	for _, candidate := range candidates {
-		if !excludeIDs[candidate.UserID] {
-			available = append(available, candidate)
+	for _, member := range members {
+		if !excludeIDs[member.UserID] {
+			available = append(available, member)
 		}
 	}
+	return available
+}
 
-	if len(available) == 0 {
-		return "", nil, fmt.Errorf("no active replacement candidate in team")
+func (s *PRService) selectReviewers(ctx context.Context, settings *models.TeamSettings, users []models.User, count int, planned map[string]int) ([]string, error) {
+	if count <= 0 || len(users) == 0 {
+		return nil, nil
 	}
 
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	newReviewer := available[r.Intn(len(available))]
+	userIDs := make([]string, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.UserID)
+	}
 
-	if reassignErr := s.prRepo.ReassignReviewer(prID, oldReviewerID, newReviewer.UserID); reassignErr != nil {
-		return "", nil, fmt.Errorf("failed to reassign reviewer: %w", reassignErr)
+	loads, err := s.prRepo.GetOpenReviewCounts(ctx, userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get open review counts: %w", err)
+	}
+
+	candidates := make([]ReviewerCandidate, 0, len(users))
+	for _, user := range users {
+		load := loads[user.UserID] + planned[user.UserID]
+		if user.MaxOpenReviews != nil && load >= *user.MaxOpenReviews {
+			continue
+		}
+		candidates = append(candidates, ReviewerCandidate{User: user, Load: load})
 	}
 
-	updatedPR, err := s.prRepo.GetPR(prID)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("select reviewers: %w", domainerrors.ErrCapacityExhausted)
+	}
+	if len(candidates) < count && len(candidates) < len(users) {
+		return nil, fmt.Errorf("select reviewers: %w", domainerrors.ErrCapacityExhausted.WithMessage(
+			fmt.Sprintf("only %d of %d reviewers available, the rest are at capacity", len(candidates), count)))
+	}
+
+	selector := s.selectors.ForTeam(settings.TeamName)
+	if settings.SelectionStrategy != "" {
+		selector = s.selectors.Get(SelectionStrategy(settings.SelectionStrategy))
+	}
+
+	return selector.Select(settings.TeamName, candidates, count), nil
+}
+
+func (s *PRService) ListPRs(ctx context.Context, filter *models.PRListFilter) (*models.PRListPage, error) {
+	if filter.Sort == "" {
+		filter.Sort = models.SortByCreatedAt
+	}
+	if filter.Order == "" {
+		filter.Order = models.SortDesc
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageLimit
+	}
+
+	statuses, err := parseStatusFilter(filter.Status)
 	if err != nil {
-		return "", nil, fmt.Errorf("failed to get updated PR: %w", err)
+		return nil, err
 	}
+	filter.Statuses = statuses
 
-	return newReviewer.UserID, updatedPR, nil
+	prs, nextCursor, err := s.prRepo.ListPRs(ctx, filter)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, fmt.Errorf("list PRs: %w", domainerrors.ErrInvalidCursor)
+		}
+		return nil, fmt.Errorf("failed to list PRs: %w", err)
+	}
+
+	return &models.PRListPage{PullRequests: prs, NextCursor: nextCursor}, nil
 }
 
-func (s *PRService) GetPRsByReviewer(reviewerID string) ([]models.PullRequestShort, error) {
-	_, err := s.userRepo.GetUser(reviewerID)
+func (s *PRService) GetPRsByReviewer(ctx context.Context, reviewerID string, filter *models.ReviewListFilter) ([]models.PullRequestShort, string, error) {
+	_, err := s.userRepo.GetUser(ctx, reviewerID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("user not found")
+			return nil, "", fmt.Errorf("get PRs by reviewer: %w", domainerrors.ErrUserNotFound)
+		}
+		return nil, "", fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultPageLimit
+	}
+
+	filter.Statuses, err = parseStatusFilter(filter.Status)
+	if err != nil {
+		return nil, "", err
+	}
+
+	prs, nextCursor, err := s.prRepo.GetPRsByReviewer(ctx, reviewerID, filter)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) {
+			return nil, "", fmt.Errorf("get PRs by reviewer: %w", domainerrors.ErrInvalidCursor)
 		}
-		return nil, fmt.Errorf("failed to get user: %w", err)
+		return nil, "", fmt.Errorf("failed to get PRs by reviewer: %w", err)
 	}
+	return prs, nextCursor, nil
+}
 
-	return s.prRepo.GetPRsByReviewer(reviewerID)
+func parseStatusFilter(value string) ([]models.PullRequestStatus, error) {
+	var statuses []models.PullRequestStatus
+	for _, part := range strings.Split(value, ",") {
+		status := models.PullRequestStatus(strings.ToUpper(strings.TrimSpace(part)))
+		if status == "" {
+			continue
+		}
+		if _, ok := statusTransitions[status]; !ok {
+			return nil, fmt.Errorf("parse status filter: %w", domainerrors.ErrInvalidStatusFilter)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
 }
@@ -0,0 +1,29 @@
+package service
+
+import (
+	"fmt"
+
+	domainerrors "github.com/avito/pr-reviewer-service/internal/domain/errors"
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+var statusTransitions = map[models.PullRequestStatus][]models.PullRequestStatus{
+	models.StatusDraft:    {models.StatusOpen, models.StatusClosed},
+	models.StatusOpen:     {models.StatusMerged, models.StatusClosed},
+	models.StatusReopened: {models.StatusMerged, models.StatusClosed},
+	models.StatusClosed:   {models.StatusReopened},
+	models.StatusMerged:   {},
+}
+
+func invalidTransition(from, to models.PullRequestStatus) error {
+	return domainerrors.ErrInvalidTransition.WithMessage(fmt.Sprintf("invalid status transition from %s to %s", from, to))
+}
+
+func validateTransition(from, to models.PullRequestStatus) error {
+	for _, allowed := range statusTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return invalidTransition(from, to)
+}
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+)
+
+type SelectionStrategy string
+
+const (
+	StrategyRandom         SelectionStrategy = "random"
+	StrategyRoundRobin     SelectionStrategy = "round_robin"
+	StrategyLeastLoaded    SelectionStrategy = "least_loaded"
+	StrategyWeightedRandom SelectionStrategy = "weighted_random"
+)
+
+type ReviewerCandidate struct {
+	User models.User
+	Load int
+}
+
+type ReviewerSelector interface {
+	Select(teamName string, candidates []ReviewerCandidate, count int) []string
+}
+
+func ParseSelectionStrategy(value string) (SelectionStrategy, error) {
+	switch strategy := SelectionStrategy(strings.TrimSpace(value)); strategy {
+	case "":
+		return StrategyLeastLoaded, nil
+	case StrategyRandom, StrategyRoundRobin, StrategyLeastLoaded, StrategyWeightedRandom:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown selection strategy %q", value)
+	}
+}
+
+func ParseTeamStrategies(value string) (map[string]SelectionStrategy, error) {
+	strategies := make(map[string]SelectionStrategy)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		teamName, strategyName, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(teamName) == "" {
+			return nil, fmt.Errorf("invalid team strategy %q, expected team=strategy", pair)
+		}
+		strategy, err := ParseSelectionStrategy(strategyName)
+		if err != nil {
+			return nil, err
+		}
+		strategies[strings.TrimSpace(teamName)] = strategy
+	}
+	return strategies, nil
+}
+
+type ReviewerSelectors struct {
+	mu              sync.RWMutex
+	defaultStrategy SelectionStrategy
+	teamStrategies  map[string]SelectionStrategy
+	selectors       map[SelectionStrategy]ReviewerSelector
+}
+
+func NewReviewerSelectors(defaultStrategy SelectionStrategy, teamStrategies map[string]SelectionStrategy) *ReviewerSelectors {
+	if defaultStrategy == "" {
+		defaultStrategy = StrategyLeastLoaded
+	}
+	if teamStrategies == nil {
+		teamStrategies = make(map[string]SelectionStrategy)
+	}
+	return &ReviewerSelectors{
+		defaultStrategy: defaultStrategy,
+		teamStrategies:  teamStrategies,
+		selectors: map[SelectionStrategy]ReviewerSelector{
+			StrategyRandom:         &RandomSelector{},
+			StrategyRoundRobin:     NewRoundRobinSelector(),
+			StrategyLeastLoaded:    &LeastLoadedSelector{},
+			StrategyWeightedRandom: &WeightedRandomSelector{},
+		},
+	}
+}
+
+func (s *ReviewerSelectors) Register(strategy SelectionStrategy, selector ReviewerSelector) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.selectors[strategy] = selector
+}
+
+func (s *ReviewerSelectors) StrategyForTeam(teamName string) SelectionStrategy {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if strategy, ok := s.teamStrategies[teamName]; ok {
+		return strategy
+	}
+	return s.defaultStrategy
+}
+
+func (s *ReviewerSelectors) Get(strategy SelectionStrategy) ReviewerSelector {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if selector, ok := s.selectors[strategy]; ok {
+		return selector
+	}
+	return s.selectors[StrategyRandom]
+}
+
+func (s *ReviewerSelectors) ForTeam(teamName string) ReviewerSelector {
+	return s.Get(s.StrategyForTeam(teamName))
+}
+
+type RandomSelector struct{}
+
+func (s *RandomSelector) Select(_ string, candidates []ReviewerCandidate, count int) []string {
+	count = min(count, len(candidates))
+	if count <= 0 {
+		return nil
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	selected := make([]string, 0, count)
+	for _, idx := range r.Perm(len(candidates))[:count] {
+		selected = append(selected, candidates[idx].User.UserID)
+	}
+	return selected
+}
+
+type RoundRobinSelector struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{last: make(map[string]string)}
+}
+
+func (s *RoundRobinSelector) Select(teamName string, candidates []ReviewerCandidate, count int) []string {
+	count = min(count, len(candidates))
+	if count <= 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.User.UserID)
+	}
+	sort.Strings(ids)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := sort.SearchStrings(ids, s.last[teamName])
+	if start < len(ids) && ids[start] == s.last[teamName] {
+		start++
+	}
+
+	selected := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		selected = append(selected, ids[(start+i)%len(ids)])
+	}
+	s.last[teamName] = selected[len(selected)-1]
+	return selected
+}
+
+type LeastLoadedSelector struct{}
+
+func (s *LeastLoadedSelector) Select(_ string, candidates []ReviewerCandidate, count int) []string {
+	count = min(count, len(candidates))
+	if count <= 0 {
+		return nil
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	sorted := make([]ReviewerCandidate, 0, len(candidates))
+	for _, idx := range r.Perm(len(candidates)) {
+		sorted = append(sorted, candidates[idx])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Load < sorted[j].Load
+	})
+
+	selected := make([]string, 0, count)
+	for _, candidate := range sorted[:count] {
+		selected = append(selected, candidate.User.UserID)
+	}
+	return selected
+}
+
+type WeightedRandomSelector struct{}
+
+func (s *WeightedRandomSelector) Select(_ string, candidates []ReviewerCandidate, count int) []string {
+	count = min(count, len(candidates))
+	if count <= 0 {
+		return nil
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	pool := append([]ReviewerCandidate(nil), candidates...)
+	selected := make([]string, 0, count)
+	for len(selected) < count {
+		total := 0.0
+		for _, candidate := range pool {
+			total += candidateWeight(candidate)
+		}
+
+		target := r.Float64() * total
+		idx := len(pool) - 1
+		for i, candidate := range pool {
+			target -= candidateWeight(candidate)
+			if target < 0 {
+				idx = i
+				break
+			}
+		}
+
+		selected = append(selected, pool[idx].User.UserID)
+		pool = append(pool[:idx], pool[idx+1:]...)
+	}
+	return selected
+}
+
+func candidateWeight(candidate ReviewerCandidate) float64 {
+	return 1 / float64(candidate.Load+1)
+}
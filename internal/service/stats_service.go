@@ -1,25 +1,26 @@
 package service
 
 import (
+	"context"
+
 	"github.com/avito/pr-reviewer-service/internal/models"
-	"github.com/avito/pr-reviewer-service/internal/repository"
 )
 
 type StatsService struct {
 	prRepo PRRepositoryInterface
 }
 
-func NewStatsService(prRepo *repository.PullRequestRepository) *StatsService {
+func NewStatsService(prRepo PRRepositoryInterface) *StatsService {
 	return &StatsService{prRepo: prRepo}
 }
 
-func (s *StatsService) GetStats() (*models.StatsResponse, error) {
-	userStats, err := s.prRepo.GetUserStats()
+func (s *StatsService) GetStats(ctx context.Context) (*models.StatsResponse, error) {
+	userStats, err := s.prRepo.GetUserStats(ctx)
 	if err != nil {
 		return nil, err
 	}
 
-	prStats, err := s.prRepo.GetPRStats()
+	prStats, err := s.prRepo.GetPRStats(ctx)
 	if err != nil {
 		return nil, err
 	}
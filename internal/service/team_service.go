@@ -1,54 +1,158 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
+	domainerrors "github.com/avito/pr-reviewer-service/internal/domain/errors"
 	"github.com/avito/pr-reviewer-service/internal/models"
-	"github.com/avito/pr-reviewer-service/internal/repository"
 )
 
+const defaultReviewerCount = 2
+
 type TeamService struct {
-	teamRepo TeamRepositoryInterface
-	userRepo UserRepositoryInterface
+	teamRepo  TeamRepositoryInterface
+	userRepo  UserRepositoryInterface
+	prService *PRService
+	txManager TxManagerInterface
 }
 
-func NewTeamService(teamRepo *repository.TeamRepository, userRepo *repository.UserRepository) *TeamService {
-	return &TeamService{teamRepo: teamRepo, userRepo: userRepo}
+func NewTeamService(teamRepo TeamRepositoryInterface, userRepo UserRepositoryInterface, prService *PRService, txManager TxManagerInterface) *TeamService {
+	return &TeamService{teamRepo: teamRepo, userRepo: userRepo, prService: prService, txManager: txManager}
 }
 
-func (s *TeamService) CreateTeam(team *models.Team) error {
-	exists, err := s.teamRepo.TeamExists(team.TeamName)
+func (s *TeamService) CreateTeam(ctx context.Context, team *models.Team) error {
+	exists, err := s.teamRepo.TeamExists(ctx, team.TeamName)
 	if err != nil {
 		return fmt.Errorf("failed to check team existence: %w", err)
 	}
 	if exists {
-		return fmt.Errorf("team already exists")
+		return fmt.Errorf("create team: %w", domainerrors.ErrTeamExists)
 	}
 
-	return s.teamRepo.CreateTeam(team)
+	return s.teamRepo.CreateTeam(ctx, team)
 }
 
-func (s *TeamService) GetTeam(teamName string) (*models.Team, error) {
-	team, err := s.teamRepo.GetTeam(teamName)
+func (s *TeamService) GetTeam(ctx context.Context, teamName string) (*models.Team, error) {
+	team, err := s.teamRepo.GetTeam(ctx, teamName)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("team not found")
+			return nil, fmt.Errorf("get team: %w", domainerrors.ErrTeamNotFound)
 		}
 		return nil, fmt.Errorf("failed to get team: %w", err)
 	}
 	return team, nil
 }
 
-func (s *TeamService) BulkDeactivateTeam(teamName string) error {
-	_, err := s.teamRepo.GetTeam(teamName)
+func (s *TeamService) BulkDeactivateTeam(ctx context.Context, teamName string, dryRun bool) (*models.BulkDeactivationReport, error) {
+	report := &models.BulkDeactivationReport{
+		TeamName: teamName,
+		DryRun:   dryRun,
+	}
+
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		team, getErr := s.teamRepo.GetTeam(ctx, teamName)
+		if getErr != nil {
+			if errors.Is(getErr, sql.ErrNoRows) {
+				return fmt.Errorf("bulk deactivate team: %w", domainerrors.ErrTeamNotFound)
+			}
+			return fmt.Errorf("failed to get team: %w", getErr)
+		}
+
+		memberIDs := make([]string, 0, len(team.Members))
+		for _, member := range team.Members {
+			memberIDs = append(memberIDs, member.UserID)
+		}
+		report.DeactivatedUsers = memberIDs
+
+		plans, planErr := s.prService.PlanTeamDeactivation(ctx, teamName, memberIDs)
+		if planErr != nil {
+			return planErr
+		}
+		report.PullRequests = plans
+		if dryRun {
+			return nil
+		}
+
+		if deactivateErr := s.userRepo.DeactivateTeamMembers(ctx, teamName); deactivateErr != nil {
+			return deactivateErr
+		}
+
+		var reassignments []models.ReviewerReassignment
+		for _, plan := range plans {
+			reassignments = append(reassignments, plan.Reassigned...)
+		}
+		return s.prService.ApplyReassignments(ctx, reassignments, models.ChangeReasonDeactivation)
+	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return fmt.Errorf("team not found")
+		return nil, fmt.Errorf("failed to deactivate team: %w", err)
+	}
+	return report, nil
+}
+
+func (s *TeamService) GetTeamSettings(ctx context.Context, teamName string) (*models.TeamSettings, error) {
+	exists, err := s.teamRepo.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check team existence: %w", err)
+	}
+	if !exists {
+		return nil, fmt.Errorf("get team settings: %w", domainerrors.ErrTeamNotFound)
+	}
+
+	return loadTeamSettings(ctx, s.teamRepo, teamName)
+}
+
+func (s *TeamService) UpdateTeamSettings(ctx context.Context, update *models.TeamSettingsUpdate) (*models.TeamSettings, error) {
+	settings, err := s.GetTeamSettings(ctx, update.TeamName)
+	if err != nil {
+		return nil, err
+	}
+
+	if update.ReviewerCount != nil {
+		settings.ReviewerCount = *update.ReviewerCount
+	}
+	if update.SelectionStrategy != nil {
+		strategy := strings.TrimSpace(*update.SelectionStrategy)
+		if strategy != "" {
+			if _, parseErr := ParseSelectionStrategy(strategy); parseErr != nil {
+				return nil, fmt.Errorf("update team settings: %w", domainerrors.ErrInvalidStrategy)
+			}
 		}
-		return fmt.Errorf("failed to get team: %w", err)
+		settings.SelectionStrategy = strategy
+	}
+	if update.MinApprovals != nil {
+		settings.MinApprovals = *update.MinApprovals
+	}
+	if update.BlockOnChangesRequested != nil {
+		settings.BlockOnChangesRequested = *update.BlockOnChangesRequested
 	}
+	if update.RequireAllApproved != nil {
+		settings.RequireAllApproved = *update.RequireAllApproved
+	}
+	if update.SLAHours != nil {
+		settings.SLAHours = *update.SLAHours
+	}
+
+	if err := s.teamRepo.UpsertTeamSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to update team settings: %w", err)
+	}
+	return settings, nil
+}
 
-	return s.userRepo.BulkDeactivateTeamMembers(teamName)
+func loadTeamSettings(ctx context.Context, teamRepo TeamRepositoryInterface, teamName string) (*models.TeamSettings, error) {
+	settings, err := teamRepo.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &models.TeamSettings{
+				TeamName:                teamName,
+				ReviewerCount:           defaultReviewerCount,
+				BlockOnChangesRequested: true,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to get team settings: %w", err)
+	}
+	return settings, nil
 }
@@ -1,33 +1,77 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
 
+	domainerrors "github.com/avito/pr-reviewer-service/internal/domain/errors"
 	"github.com/avito/pr-reviewer-service/internal/models"
-	"github.com/avito/pr-reviewer-service/internal/repository"
 )
 
 type UserService struct {
-	userRepo UserRepositoryInterface
+	userRepo  UserRepositoryInterface
+	prService *PRService
+	txManager TxManagerInterface
 }
 
-func NewUserService(userRepo *repository.UserRepository) *UserService {
-	return &UserService{userRepo: userRepo}
+func NewUserService(userRepo UserRepositoryInterface, prService *PRService, txManager TxManagerInterface) *UserService {
+	return &UserService{userRepo: userRepo, prService: prService, txManager: txManager}
 }
 
-func (s *UserService) SetIsActive(userID string, isActive bool) (*models.User, error) {
-	user, err := s.userRepo.SetIsActive(userID, isActive)
+func (s *UserService) SetIsActive(ctx context.Context, userID string, isActive bool, reassignReviews bool) (*models.User, *models.ReassignmentReport, error) {
+	if isActive || !reassignReviews {
+		user, err := s.userRepo.SetIsActive(ctx, userID, isActive)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, nil, fmt.Errorf("set is active: %w", domainerrors.ErrUserNotFound)
+			}
+			return nil, nil, fmt.Errorf("failed to update user: %w", err)
+		}
+		return user, nil, nil
+	}
+
+	var user *models.User
+	var report *models.ReassignmentReport
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		current, err := s.userRepo.GetUser(ctx, userID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return fmt.Errorf("set is active: %w", domainerrors.ErrUserNotFound)
+			}
+			return fmt.Errorf("failed to get user: %w", err)
+		}
+
+		report, err = s.prService.PlanReviewerReplacements(ctx, current)
+		if err != nil {
+			return err
+		}
+
+		user, err = s.userRepo.SetIsActive(ctx, userID, false)
+		if err != nil {
+			return fmt.Errorf("failed to deactivate user: %w", err)
+		}
+
+		return s.prService.ApplyReassignments(ctx, report.Reassigned, models.ChangeReasonDeactivation)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, report, nil
+}
+
+func (s *UserService) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (*models.User, error) {
+	user, err := s.userRepo.SetMaxOpenReviews(ctx, userID, maxOpenReviews)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("user not found")
+			return nil, fmt.Errorf("set max open reviews: %w", domainerrors.ErrUserNotFound)
 		}
 		return nil, fmt.Errorf("failed to update user: %w", err)
 	}
 	return user, nil
 }
 
-func (s *UserService) GetActiveTeamMembers(teamName string, excludeUserID string) ([]models.User, error) {
-	return s.userRepo.GetActiveTeamMembers(teamName, excludeUserID)
+func (s *UserService) GetActiveTeamMembers(ctx context.Context, teamName string, excludeUserID string) ([]models.User, error) {
+	return s.userRepo.GetActiveTeamMembers(ctx, teamName, excludeUserID)
 }
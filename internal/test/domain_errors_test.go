@@ -0,0 +1,271 @@
+package test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/avito/pr-reviewer-service/internal/database"
+	domainerrors "github.com/avito/pr-reviewer-service/internal/domain/errors"
+	"github.com/avito/pr-reviewer-service/internal/models"
+	"github.com/avito/pr-reviewer-service/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openAPIErrorCodes(t *testing.T) []string {
+	file, err := os.Open("../../openapi.yml")
+	require.NoError(t, err)
+	defer file.Close() //nolint:errcheck
+
+	var codes []string
+	inErrorResponse, inEnum := false, false
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "ErrorResponse:":
+			inErrorResponse = true
+		case inErrorResponse && line == "enum:":
+			inEnum = true
+		case inEnum && strings.HasPrefix(line, "- "):
+			codes = append(codes, strings.TrimPrefix(line, "- "))
+		case inEnum:
+			return codes
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return codes
+}
+
+func TestDomainErrorsWrapping(t *testing.T) {
+	err := fmt.Errorf("reassign reviewer: %w", domainerrors.ErrPRMerged)
+	assert.ErrorIs(t, err, domainerrors.ErrPRMerged)
+	assert.NotErrorIs(t, err, domainerrors.ErrReviewMerged)
+	assert.Equal(t, "cannot reassign on merged PR", domainerrors.ErrPRMerged.Error())
+
+	blocked := domainerrors.ErrMergeBlocked.WithDetails([]string{"requires at least 1 approvals, has 0"})
+	err = fmt.Errorf("merge PR: %w", blocked)
+	assert.ErrorIs(t, err, domainerrors.ErrMergeBlocked)
+	assert.Equal(t, domainerrors.CodeMergeBlocked, blocked.Code)
+	assert.Empty(t, domainerrors.ErrMergeBlocked.Details)
+
+	transition := domainerrors.ErrInvalidTransition.WithMessage("invalid status transition from MERGED to OPEN").WithDetails(nil)
+	assert.ErrorIs(t, transition, domainerrors.ErrInvalidTransition)
+	assert.Equal(t, http.StatusConflict, transition.Status)
+	assert.Equal(t, "invalid status transition from MERGED to OPEN", transition.Message)
+}
+
+func TestErrorCodesFromOpenAPI(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name IN ('codes', 'codes-full')")      //nolint:errcheck
+	_, _ = db.Exec("DELETE FROM idempotency_keys WHERE idempotency_key LIKE 'codes-%'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postWithKey := func(path, key string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	postJSON := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		return postWithKey(path, "", payload)
+	}
+
+	team := models.Team{
+		TeamName: "codes",
+		Members: []models.TeamMember{
+			{UserID: "cd1", Username: "Author", IsActive: true},
+			{UserID: "cd2", Username: "Reviewer", IsActive: true},
+			{UserID: "cd3", Username: "Bystander", IsActive: false},
+		},
+	}
+	w := postJSON("/team/add", team)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	for _, prID := range []string{"pr-codes-open", "pr-codes-merged"} {
+		w = postJSON("/pullRequest/create", map[string]string{
+			"pull_request_id":   prID,
+			"pull_request_name": "Codes",
+			"author_id":         "cd1",
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+	w = postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-codes-merged"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = postJSON("/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   "pr-codes-draft",
+		"pull_request_name": "Codes",
+		"author_id":         "cd1",
+		"draft":             true,
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/team/settings", map[string]interface{}{"team_name": "codes", "min_approvals": 1})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = postJSON("/team/add", models.Team{
+		TeamName: "codes-full",
+		Members: []models.TeamMember{
+			{UserID: "cf1", Username: "Author", IsActive: true},
+			{UserID: "cf2", Username: "Busy", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = postJSON("/users/setMaxOpenReviews", map[string]interface{}{"user_id": "cf2", "max_open_reviews": 0})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	suffix := fmt.Sprint(time.Now().UnixNano())
+	reusedKey := "codes-reused-" + suffix
+	w = postWithKey("/team/settings", reusedKey, map[string]interface{}{"team_name": "codes", "sla_hours": 24})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	pendingKey := "codes-pending-" + suffix
+	pendingBody := map[string]interface{}{"team_name": "codes", "sla_hours": 48}
+	encoded, _ := json.Marshal(pendingBody)
+	hash := sha256.Sum256(append([]byte("POST /team/settings\n"), encoded...))
+	_, reserved, err := repository.NewIdempotencyRepository(db).Reserve(context.Background(), pendingKey, hex.EncodeToString(hash[:]), time.Hour)
+	require.NoError(t, err)
+	require.True(t, reserved)
+
+	closedDB, err := database.NewDB()
+	require.NoError(t, err)
+	require.NoError(t, closedDB.Close())
+	broken := newRouter(
+		closedDB,
+		repository.NewTeamRepository(closedDB),
+		repository.NewUserRepository(closedDB),
+		repository.NewPullRequestRepository(closedDB),
+		repository.NewAbsenceRepository(closedDB),
+		repository.NewTxManager(closedDB),
+		repository.NewIdempotencyRepository(closedDB),
+	)
+
+	triggers := map[string]func() *httptest.ResponseRecorder{
+		domainerrors.CodeTeamExists: func() *httptest.ResponseRecorder {
+			return postJSON("/team/add", team)
+		},
+		domainerrors.CodePRExists: func() *httptest.ResponseRecorder {
+			return postJSON("/pullRequest/create", map[string]string{
+				"pull_request_id":   "pr-codes-open",
+				"pull_request_name": "Codes",
+				"author_id":         "cd1",
+			})
+		},
+		domainerrors.CodePRMerged: func() *httptest.ResponseRecorder {
+			return postJSON("/pullRequest/reassign", map[string]string{"pull_request_id": "pr-codes-merged", "old_user_id": "cd2"})
+		},
+		domainerrors.CodeNotAssigned: func() *httptest.ResponseRecorder {
+			return postJSON("/pullRequest/reassign", map[string]string{"pull_request_id": "pr-codes-open", "old_user_id": "cd3"})
+		},
+		domainerrors.CodeNoCandidate: func() *httptest.ResponseRecorder {
+			return postJSON("/pullRequest/reassign", map[string]string{"pull_request_id": "pr-codes-open", "old_user_id": "cd2"})
+		},
+		domainerrors.CodeNotFound: func() *httptest.ResponseRecorder {
+			req, _ := http.NewRequest("GET", "/team/get?team_name=codes-missing", nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			return resp
+		},
+		domainerrors.CodeInvalidRequest: func() *httptest.ResponseRecorder {
+			return postJSON("/pullRequest/create", map[string]string{"pull_request_id": "pr-codes-invalid"})
+		},
+		domainerrors.CodeForbidden: func() *httptest.ResponseRecorder {
+			return postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "pr-codes-open", "force": true})
+		},
+		domainerrors.CodeMergeBlocked: func() *httptest.ResponseRecorder {
+			return postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-codes-open"})
+		},
+		domainerrors.CodeInvalidTransition: func() *httptest.ResponseRecorder {
+			return postJSON("/pullRequest/reopen", map[string]string{"pull_request_id": "pr-codes-open"})
+		},
+		domainerrors.CodePRNotOpen: func() *httptest.ResponseRecorder {
+			return postJSON("/pullRequest/addReviewer", map[string]string{"pull_request_id": "pr-codes-draft", "user_id": "cd2"})
+		},
+		domainerrors.CodeCapacityExhausted: func() *httptest.ResponseRecorder {
+			return postJSON("/pullRequest/create", map[string]string{
+				"pull_request_id":   "pr-codes-full",
+				"pull_request_name": "Codes",
+				"author_id":         "cf1",
+			})
+		},
+		domainerrors.CodeWrongTeam: func() *httptest.ResponseRecorder {
+			return postJSON("/pullRequest/reassign", map[string]string{"pull_request_id": "pr-codes-open", "old_user_id": "cd2", "new_user_id": "cf2"})
+		},
+		domainerrors.CodeIdempotencyReused: func() *httptest.ResponseRecorder {
+			return postWithKey("/team/settings", reusedKey, map[string]interface{}{"team_name": "codes", "sla_hours": 12})
+		},
+		domainerrors.CodeIdempotencyPending: func() *httptest.ResponseRecorder {
+			return postWithKey("/team/settings", pendingKey, pendingBody)
+		},
+		domainerrors.CodeTimeout: func() *httptest.ResponseRecorder {
+			ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+			defer cancel()
+			req, _ := http.NewRequestWithContext(ctx, "GET", "/team/get?team_name=codes", nil)
+			resp := httptest.NewRecorder()
+			r.ServeHTTP(resp, req)
+			return resp
+		},
+		domainerrors.CodeInternal: func() *httptest.ResponseRecorder {
+			req, _ := http.NewRequest("GET", "/team/get?team_name=codes", nil)
+			resp := httptest.NewRecorder()
+			broken.ServeHTTP(resp, req)
+			return resp
+		},
+	}
+	expectedStatus := map[string]int{
+		domainerrors.CodeTeamExists:         http.StatusBadRequest,
+		domainerrors.CodePRExists:           http.StatusConflict,
+		domainerrors.CodePRMerged:           http.StatusConflict,
+		domainerrors.CodeNotAssigned:        http.StatusConflict,
+		domainerrors.CodeNoCandidate:        http.StatusConflict,
+		domainerrors.CodeNotFound:           http.StatusNotFound,
+		domainerrors.CodeInvalidRequest:     http.StatusBadRequest,
+		domainerrors.CodeForbidden:          http.StatusForbidden,
+		domainerrors.CodeMergeBlocked:       http.StatusConflict,
+		domainerrors.CodeInvalidTransition:  http.StatusConflict,
+		domainerrors.CodePRNotOpen:          http.StatusConflict,
+		domainerrors.CodeCapacityExhausted:  http.StatusConflict,
+		domainerrors.CodeWrongTeam:          http.StatusConflict,
+		domainerrors.CodeIdempotencyReused:  http.StatusUnprocessableEntity,
+		domainerrors.CodeIdempotencyPending: http.StatusConflict,
+		domainerrors.CodeTimeout:            http.StatusGatewayTimeout,
+		domainerrors.CodeInternal:           http.StatusInternalServerError,
+	}
+
+	codes := openAPIErrorCodes(t)
+	require.NotEmpty(t, codes)
+	for _, code := range codes {
+		trigger, ok := triggers[code]
+		require.True(t, ok, "no test trigger for openapi code %s", code)
+
+		resp := trigger()
+		assert.Equal(t, expectedStatus[code], resp.Code, code)
+
+		var response models.ErrorResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+		assert.Equal(t, code, response.Error.Code)
+		assert.NotEmpty(t, response.Error.Message)
+	}
+}
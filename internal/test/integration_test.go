@@ -2,14 +2,23 @@ package test
 
 import (
 	"bytes"
+	"context"
+	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/avito/pr-reviewer-service/internal/database"
 	"github.com/avito/pr-reviewer-service/internal/handler"
+	"github.com/avito/pr-reviewer-service/internal/middleware"
 	"github.com/avito/pr-reviewer-service/internal/models"
 	"github.com/avito/pr-reviewer-service/internal/repository"
 	"github.com/avito/pr-reviewer-service/internal/router"
@@ -19,7 +28,19 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+const testAdminToken = "test-admin-token"
+
+var (
+	sqliteSchemaOnce sync.Once
+	sqliteSchemaErr  error
+)
+
 func setupTestDB(t *testing.T) {
+	if os.Getenv("DB_DRIVER") == database.DriverSQLite {
+		sqliteSchemaOnce.Do(func() { sqliteSchemaErr = prepareSQLiteDB() })
+		require.NoError(t, sqliteSchemaErr)
+		return
+	}
 	if os.Getenv("DB_HOST") == "" {
 		os.Setenv("DB_HOST", "localhost")     //nolint:errcheck
 	}
@@ -40,6 +61,34 @@ func setupTestDB(t *testing.T) {
 	}
 }
 
+func prepareSQLiteDB() error {
+	if os.Getenv("DB_PATH") == "" {
+		os.Setenv("DB_PATH", filepath.Join(os.TempDir(), fmt.Sprintf("pr_reviewer_test_%d.db", os.Getpid()))) //nolint:errcheck
+	}
+	for _, suffix := range []string{"", "-wal", "-shm"} {
+		if err := os.Remove(os.Getenv("DB_PATH") + suffix); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	db, err := database.NewDB()
+	if err != nil {
+		return err
+	}
+	defer db.Close() //nolint:errcheck
+
+	fsys, err := database.EmbeddedMigrations(database.DriverSQLite)
+	if err != nil {
+		return err
+	}
+	migrator, err := database.NewMigrator(db, database.DriverSQLite, fsys)
+	if err != nil {
+		return err
+	}
+	_, err = migrator.Up(context.Background())
+	return err
+}
+
 func setupRouter(t *testing.T) *gin.Engine {
 	setupTestDB(t)
 	db, err := database.NewDB()
@@ -47,24 +96,45 @@ func setupRouter(t *testing.T) *gin.Engine {
 		t.Fatalf("Failed to connect to database: %v", err)
 	}
 
-	teamRepo := repository.NewTeamRepository(db)
-	userRepo := repository.NewUserRepository(db)
-	prRepo := repository.NewPullRequestRepository(db)
+	return newRouter(
+		db,
+		repository.NewTeamRepository(db),
+		repository.NewUserRepository(db),
+		repository.NewPullRequestRepository(db),
+		repository.NewAbsenceRepository(db),
+		repository.NewTxManager(db),
+		repository.NewIdempotencyRepository(db),
+	)
+}
 
-	teamService := service.NewTeamService(teamRepo, userRepo)
-	userService := service.NewUserService(userRepo)
-	prService := service.NewPRService(prRepo, userRepo)
+func newRouter(
+	db *sql.DB,
+	teamRepo service.TeamRepositoryInterface,
+	userRepo service.UserRepositoryInterface,
+	prRepo service.PRRepositoryInterface,
+	absenceRepo service.AbsenceRepositoryInterface,
+	txManager service.TxManagerInterface,
+	idempotencyStore middleware.IdempotencyStore,
+) *gin.Engine {
+	selectors := service.NewReviewerSelectors(service.StrategyLeastLoaded, nil)
+
+	prService := service.NewPRService(prRepo, userRepo, teamRepo, selectors)
+	teamService := service.NewTeamService(teamRepo, userRepo, prService, txManager)
+	userService := service.NewUserService(userRepo, prService, txManager)
+	absenceService := service.NewAbsenceService(absenceRepo, userRepo)
 	statsService := service.NewStatsService(prRepo)
 
 	teamHandler := handler.NewTeamHandler(teamService)
 	userHandler := handler.NewUserHandler(userService, prService)
-	prHandler := handler.NewPRHandler(prService)
+	absenceHandler := handler.NewAbsenceHandler(absenceService)
+	prHandler := handler.NewPRHandler(prService, testAdminToken)
 	statsHandler := handler.NewStatsHandler(statsService)
 	healthHandler := handler.NewHealthHandler(db)
 	metricsHandler := handler.NewMetricsHandler()
+	idempotency := middleware.Idempotency(idempotencyStore, time.Hour)
 
 	gin.SetMode(gin.TestMode)
-	return router.SetupRouter(teamHandler, userHandler, prHandler, statsHandler, healthHandler, metricsHandler)
+	return router.SetupRouter(teamHandler, userHandler, absenceHandler, prHandler, statsHandler, healthHandler, metricsHandler, idempotency)
 }
 
 func TestHealthCheck(t *testing.T) {
@@ -334,3 +404,1717 @@ func TestNotFound(t *testing.T) {
 
 	assert.Equal(t, http.StatusNotFound, w.Code)
 }
+
+func TestCreatePRBalancesOpenReviews(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'balance'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	team := models.Team{
+		TeamName: "balance",
+		Members: []models.TeamMember{
+			{UserID: "b1", Username: "Author", IsActive: true},
+			{UserID: "b2", Username: "Reviewer2", IsActive: true},
+			{UserID: "b3", Username: "Reviewer3", IsActive: true},
+			{UserID: "b4", Username: "Reviewer4", IsActive: true},
+		},
+	}
+
+	body, _ := json.Marshal(team)
+	req, _ := http.NewRequest("POST", "/team/add", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	for i := 1; i <= 6; i++ {
+		prReq := map[string]string{
+			"pull_request_id":   "pr-balance-" + string(rune('0'+i)),
+			"pull_request_name": "Balance PR",
+			"author_id":         "b1",
+		}
+		body, _ = json.Marshal(prReq)
+		req, _ = http.NewRequest("POST", "/pullRequest/create", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	for _, userID := range []string{"b2", "b3", "b4"} {
+		req, _ = http.NewRequest("GET", "/users/getReview?user_id="+userID, nil)
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+		prs := response["pull_requests"].([]interface{})
+		assert.Len(t, prs, 4, userID)
+	}
+}
+
+func TestTeamSettingsReviewerCount(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'docs'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	team := models.Team{
+		TeamName: "docs",
+		Members: []models.TeamMember{
+			{UserID: "d1", Username: "Writer", IsActive: true},
+			{UserID: "d2", Username: "Editor", IsActive: true},
+			{UserID: "d3", Username: "Proofreader", IsActive: true},
+		},
+	}
+	body, _ := json.Marshal(team)
+	req, _ := http.NewRequest("POST", "/team/add", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	req, _ = http.NewRequest("GET", "/team/settings?team_name=docs", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var settings struct {
+		Settings models.TeamSettings `json:"settings"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &settings))
+	assert.Equal(t, 2, settings.Settings.ReviewerCount)
+
+	body, _ = json.Marshal(map[string]interface{}{"team_name": "docs", "selection_strategy": "fastest"})
+	req, _ = http.NewRequest("POST", "/team/settings", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	body, _ = json.Marshal(map[string]interface{}{"team_name": "docs", "reviewer_count": 1, "selection_strategy": "round_robin"})
+	req, _ = http.NewRequest("POST", "/team/settings", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	prReq := map[string]string{
+		"pull_request_id":   "pr-docs-1",
+		"pull_request_name": "Fix typos",
+		"author_id":         "d1",
+	}
+	body, _ = json.Marshal(prReq)
+	req, _ = http.NewRequest("POST", "/pullRequest/create", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+	pr := response["pr"].(map[string]interface{})
+	assert.Len(t, pr["assigned_reviewers"].([]interface{}), 1)
+}
+
+func TestCreatePRCapacityExhausted(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'capacity'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	team := models.Team{
+		TeamName: "capacity",
+		Members: []models.TeamMember{
+			{UserID: "c1", Username: "Author", IsActive: true},
+			{UserID: "c2", Username: "Busy", IsActive: true},
+			{UserID: "c3", Username: "Busier", IsActive: true},
+		},
+	}
+	body, _ := json.Marshal(team)
+	req, _ := http.NewRequest("POST", "/team/add", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	for _, userID := range []string{"c2", "c3"} {
+		body, _ = json.Marshal(map[string]interface{}{"user_id": userID, "max_open_reviews": 1})
+		req, _ = http.NewRequest("POST", "/users/setMaxOpenReviews", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	for i, expected := range []int{http.StatusCreated, http.StatusConflict} {
+		prReq := map[string]string{
+			"pull_request_id":   "pr-capacity-" + string(rune('1'+i)),
+			"pull_request_name": "Capacity PR",
+			"author_id":         "c1",
+		}
+		body, _ = json.Marshal(prReq)
+		req, _ = http.NewRequest("POST", "/pullRequest/create", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, expected, w.Code)
+	}
+
+	var response models.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+	assert.Equal(t, "CAPACITY_EXHAUSTED", response.Error.Code)
+}
+
+func TestCreatePRPartiallySaturatedTeam(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'saturated'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := postJSON("/team/add", models.Team{
+		TeamName: "saturated",
+		Members: []models.TeamMember{
+			{UserID: "sat1", Username: "Author", IsActive: true},
+			{UserID: "sat2", Username: "Saturated", IsActive: true},
+			{UserID: "sat3", Username: "Free", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/users/setMaxOpenReviews", map[string]interface{}{"user_id": "sat2", "max_open_reviews": 0})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	prReq := map[string]string{
+		"pull_request_id":   "pr-saturated-1",
+		"pull_request_name": "One of two saturated",
+		"author_id":         "sat1",
+	}
+	w = postJSON("/pullRequest/create", prReq)
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	var response models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "CAPACITY_EXHAUSTED", response.Error.Code)
+	assert.Contains(t, response.Error.Message, "only 1 of 2 reviewers")
+
+	w = postJSON("/users/setMaxOpenReviews", map[string]interface{}{"user_id": "sat2", "max_open_reviews": nil})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = postJSON("/pullRequest/create", prReq)
+	require.Equal(t, http.StatusCreated, w.Code)
+	var created struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &created))
+	assert.ElementsMatch(t, []string{"sat2", "sat3"}, created.PR.AssignedReviewers)
+}
+
+func TestAbsentUserIsNotAssigned(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'vacation'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	team := models.Team{
+		TeamName: "vacation",
+		Members: []models.TeamMember{
+			{UserID: "v1", Username: "Author", IsActive: true},
+			{UserID: "v2", Username: "Traveler", IsActive: true},
+			{UserID: "v3", Username: "Stayer", IsActive: true},
+		},
+	}
+	body, _ := json.Marshal(team)
+	req, _ := http.NewRequest("POST", "/team/add", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	now := time.Now().UTC()
+	body, _ = json.Marshal(map[string]interface{}{
+		"user_id":   "v2",
+		"starts_at": now.Add(-time.Hour),
+		"ends_at":   now.Add(24 * time.Hour),
+		"reason":    "vacation",
+	})
+	req, _ = http.NewRequest("POST", "/users/absences", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created map[string]map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	absenceID := fmt.Sprintf("%.0f", created["absence"]["absence_id"])
+
+	prReq := map[string]string{
+		"pull_request_id":   "pr-vacation-1",
+		"pull_request_name": "While away",
+		"author_id":         "v1",
+	}
+	body, _ = json.Marshal(prReq)
+	req, _ = http.NewRequest("POST", "/pullRequest/create", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var response map[string]interface{}
+	json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+	pr := response["pr"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"v3"}, pr["assigned_reviewers"])
+
+	req, _ = http.NewRequest("DELETE", "/users/absences?absence_id="+absenceID, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	req, _ = http.NewRequest("DELETE", "/users/absences?absence_id="+absenceID, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeactivateUserReassignsOpenReviews(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'leave'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	team := models.Team{
+		TeamName: "leave",
+		Members: []models.TeamMember{
+			{UserID: "l1", Username: "Author", IsActive: true},
+			{UserID: "l2", Username: "Reviewer2", IsActive: true},
+			{UserID: "l3", Username: "Reviewer3", IsActive: true},
+			{UserID: "l4", Username: "Reviewer4", IsActive: true},
+		},
+	}
+	body, _ := json.Marshal(team)
+	req, _ := http.NewRequest("POST", "/team/add", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	prReq := map[string]string{
+		"pull_request_id":   "pr-leave-1",
+		"pull_request_name": "Before leave",
+		"author_id":         "l1",
+	}
+	body, _ = json.Marshal(prReq)
+	req, _ = http.NewRequest("POST", "/pullRequest/create", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	require.Len(t, created.PR.AssignedReviewers, 2)
+	first, second := created.PR.AssignedReviewers[0], created.PR.AssignedReviewers[1]
+
+	deactivate := func(userID string) models.ReassignmentReport {
+		body, _ := json.Marshal(map[string]interface{}{"user_id": userID, "is_active": false, "reassign_reviews": true})
+		req, _ := http.NewRequest("POST", "/users/setIsActive", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Reassignment models.ReassignmentReport `json:"reassignment"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+		return response.Reassignment
+	}
+
+	report := deactivate(first)
+	require.Len(t, report.Reassigned, 1)
+	assert.Empty(t, report.NoCandidate)
+	assert.Equal(t, "pr-leave-1", report.Reassigned[0].PullRequestID)
+	assert.NotContains(t, []string{"l1", first, second}, report.Reassigned[0].NewReviewerID)
+
+	report = deactivate(second)
+	assert.Empty(t, report.Reassigned)
+	require.Len(t, report.NoCandidate, 1)
+	assert.Equal(t, "pr-leave-1", report.NoCandidate[0].PullRequestID)
+}
+
+func TestBulkDeactivateTeamReassignsPendingReviews(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name IN ('origin', 'sunset')") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := postJSON("/team/add", models.Team{
+		TeamName: "origin",
+		Members: []models.TeamMember{
+			{UserID: "o1", Username: "Author", IsActive: true},
+			{UserID: "o2", Username: "Reviewer2", IsActive: true},
+			{UserID: "o3", Username: "Reviewer3", IsActive: true},
+			{UserID: "o4", Username: "Reviewer4", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-origin-1",
+		"pull_request_name": "Cross team",
+		"author_id":         "o1",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	require.Len(t, created.PR.AssignedReviewers, 2)
+	moved := created.PR.AssignedReviewers[0]
+
+	w = postJSON("/team/add", models.Team{
+		TeamName: "sunset",
+		Members:  []models.TeamMember{{UserID: moved, Username: "Mover", IsActive: true}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	type bulkResponse struct {
+		Report models.BulkDeactivationReport `json:"report"`
+	}
+
+	w = postJSON("/team/bulkDeactivate?dry_run=true", map[string]string{"team_name": "sunset"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var preview bulkResponse
+	json.Unmarshal(w.Body.Bytes(), &preview) //nolint:errcheck
+	assert.True(t, preview.Report.DryRun)
+	require.Len(t, preview.Report.PullRequests, 1)
+	require.Len(t, preview.Report.PullRequests[0].Reassigned, 1)
+	replacement := preview.Report.PullRequests[0].Reassigned[0].NewReviewerID
+	assert.NotContains(t, append([]string{"o1"}, created.PR.AssignedReviewers...), replacement)
+
+	req, _ := http.NewRequest("GET", "/team/get?team_name=sunset", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var team models.Team
+	json.Unmarshal(w.Body.Bytes(), &team) //nolint:errcheck
+	assert.True(t, team.Members[0].IsActive)
+
+	w = postJSON("/team/bulkDeactivate", map[string]string{"team_name": "sunset"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var applied bulkResponse
+	json.Unmarshal(w.Body.Bytes(), &applied) //nolint:errcheck
+	assert.False(t, applied.Report.DryRun)
+	require.Len(t, applied.Report.PullRequests, 1)
+	assert.Equal(t, replacement, applied.Report.PullRequests[0].Reassigned[0].NewReviewerID)
+
+	req, _ = http.NewRequest("GET", "/users/getReview?user_id="+moved, nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var reviews struct {
+		PullRequests []models.PullRequestShort `json:"pull_requests"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &reviews) //nolint:errcheck
+	assert.Empty(t, reviews.PullRequests)
+}
+
+func TestSubmitReview(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'verdict'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := postJSON("/team/add", models.Team{
+		TeamName: "verdict",
+		Members: []models.TeamMember{
+			{UserID: "r1", Username: "Author", IsActive: true},
+			{UserID: "r2", Username: "Reviewer", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-verdict-1",
+		"pull_request_name": "Needs review",
+		"author_id":         "r1",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/pullRequest/review", map[string]string{
+		"pull_request_id": "pr-verdict-1",
+		"reviewer_id":     "r2",
+		"state":           "LGTM",
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postJSON("/pullRequest/review", map[string]string{
+		"pull_request_id": "pr-verdict-1",
+		"reviewer_id":     "r1",
+		"state":           "APPROVED",
+	})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	w = postJSON("/pullRequest/review", map[string]string{
+		"pull_request_id": "pr-verdict-1",
+		"reviewer_id":     "r2",
+		"state":           "APPROVED",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	req, _ := http.NewRequest("GET", "/pullRequest/get?pull_request_id=pr-verdict-1", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+	require.Len(t, response.PR.Reviewers, 1)
+	assert.Equal(t, models.ReviewApproved, response.PR.Reviewers[0].State)
+	assert.NotNil(t, response.PR.Reviewers[0].ReviewedAt)
+}
+
+func TestMergePolicyBlocksUnapprovedPR(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'policy'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path string, payload interface{}, headers ...string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		for i := 0; i+1 < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := postJSON("/team/add", models.Team{
+		TeamName: "policy",
+		Members: []models.TeamMember{
+			{UserID: "m1", Username: "Author", IsActive: true},
+			{UserID: "m2", Username: "Strict", IsActive: true},
+			{UserID: "m3", Username: "Lenient", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/team/settings", map[string]interface{}{"team_name": "policy", "min_approvals": 2})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	for _, prID := range []string{"pr-policy-1", "pr-policy-2"} {
+		w = postJSON("/pullRequest/create", map[string]string{
+			"pull_request_id":   prID,
+			"pull_request_name": "Guarded",
+			"author_id":         "m1",
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	w = postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-policy-1", "reviewer_id": "m2", "state": "CHANGES_REQUESTED"})
+	require.Equal(t, http.StatusOK, w.Code)
+	w = postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-policy-1", "reviewer_id": "m3", "state": "APPROVED"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-policy-1"})
+	require.Equal(t, http.StatusConflict, w.Code)
+
+	var blocked models.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &blocked) //nolint:errcheck
+	assert.Equal(t, "MERGE_BLOCKED", blocked.Error.Code)
+	assert.Len(t, blocked.Error.Details, 2)
+
+	w = postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "pr-policy-1", "force": true})
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = postJSON("/pullRequest/merge", map[string]interface{}{"pull_request_id": "pr-policy-1", "force": true}, "X-Admin-Token", testAdminToken)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	for _, reviewerID := range []string{"m2", "m3"} {
+		w = postJSON("/pullRequest/review", map[string]string{"pull_request_id": "pr-policy-2", "reviewer_id": reviewerID, "state": "APPROVED"})
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+	w = postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-policy-2"})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestPRStatusLifecycle(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'lifecycle'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	decodePR := func(w *httptest.ResponseRecorder) models.PullRequest {
+		var response struct {
+			PR models.PullRequest `json:"pr"`
+		}
+		json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+		return response.PR
+	}
+
+	w := postJSON("/team/add", models.Team{
+		TeamName: "lifecycle",
+		Members: []models.TeamMember{
+			{UserID: "l1", Username: "Author", IsActive: true},
+			{UserID: "l2", Username: "Reviewer", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/pullRequest/create", map[string]interface{}{
+		"pull_request_id":   "pr-lifecycle",
+		"pull_request_name": "Work in progress",
+		"author_id":         "l1",
+		"draft":             true,
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	pr := decodePR(w)
+	assert.Equal(t, models.StatusDraft, pr.Status)
+	assert.Empty(t, pr.AssignedReviewers)
+
+	w = postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-lifecycle"})
+	require.Equal(t, http.StatusConflict, w.Code)
+	var errResp models.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &errResp) //nolint:errcheck
+	assert.Equal(t, "INVALID_TRANSITION", errResp.Error.Code)
+
+	w = postJSON("/pullRequest/ready", map[string]string{"pull_request_id": "pr-lifecycle"})
+	require.Equal(t, http.StatusOK, w.Code)
+	pr = decodePR(w)
+	assert.Equal(t, models.StatusOpen, pr.Status)
+	assert.Equal(t, []string{"l2"}, pr.AssignedReviewers)
+
+	w = postJSON("/pullRequest/close", map[string]string{"pull_request_id": "pr-lifecycle"})
+	require.Equal(t, http.StatusOK, w.Code)
+	pr = decodePR(w)
+	assert.Equal(t, models.StatusClosed, pr.Status)
+	assert.NotNil(t, pr.ClosedAt)
+
+	w = postJSON("/pullRequest/reassign", map[string]string{"pull_request_id": "pr-lifecycle", "old_user_id": "l2"})
+	require.Equal(t, http.StatusConflict, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &errResp) //nolint:errcheck
+	assert.Equal(t, "PR_NOT_OPEN", errResp.Error.Code)
+
+	w = postJSON("/pullRequest/reopen", map[string]string{"pull_request_id": "pr-lifecycle"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.StatusReopened, decodePR(w).Status)
+
+	w = postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-lifecycle"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, models.StatusMerged, decodePR(w).Status)
+
+	w = postJSON("/pullRequest/reopen", map[string]string{"pull_request_id": "pr-lifecycle"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestGetPRDetails(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'details'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := postJSON("/team/add", models.Team{
+		TeamName: "details",
+		Members: []models.TeamMember{
+			{UserID: "d1", Username: "Author", IsActive: true},
+			{UserID: "d2", Username: "First", IsActive: true},
+			{UserID: "d3", Username: "Second", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/team/settings", map[string]interface{}{"team_name": "details", "reviewer_count": 1})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-details-1",
+		"pull_request_name": "Inspect me",
+		"author_id":         "d1",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	require.Len(t, created.PR.AssignedReviewers, 1)
+	oldReviewer := created.PR.AssignedReviewers[0]
+
+	w = postJSON("/pullRequest/reassign", map[string]string{"pull_request_id": "pr-details-1", "old_user_id": oldReviewer})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	req, _ := http.NewRequest("GET", "/pullRequest/get?pull_request_id=pr-details-1", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+	assert.Equal(t, "details", response.PR.AuthorTeam)
+	require.Len(t, response.PR.Reviewers, 1)
+	assert.NotEqual(t, oldReviewer, response.PR.Reviewers[0].ReviewerID)
+	assert.NotNil(t, response.PR.Reviewers[0].AssignedAt)
+	require.Len(t, response.PR.ReassignmentHistory, 1)
+	assert.Equal(t, oldReviewer, response.PR.ReassignmentHistory[0].OldReviewerID)
+	assert.Equal(t, response.PR.Reviewers[0].ReviewerID, response.PR.ReassignmentHistory[0].NewReviewerID)
+	assert.Equal(t, models.ChangeReasonReassign, response.PR.ReassignmentHistory[0].Reason)
+
+	req, _ = http.NewRequest("GET", "/pullRequest/get?pull_request_id=pr-details-missing", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestListPRs(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'listing'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	list := func(query string) (*httptest.ResponseRecorder, models.PRListPage) {
+		req, _ := http.NewRequest("GET", "/pullRequest/list?"+query, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var page models.PRListPage
+		json.Unmarshal(w.Body.Bytes(), &page) //nolint:errcheck
+		return w, page
+	}
+
+	w := postJSON("/team/add", models.Team{
+		TeamName: "listing",
+		Members: []models.TeamMember{
+			{UserID: "ls1", Username: "Author", IsActive: true},
+			{UserID: "ls2", Username: "Reviewer", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	for i := 1; i <= 5; i++ {
+		w = postJSON("/pullRequest/create", map[string]string{
+			"pull_request_id":   fmt.Sprintf("pr-listing-%d", i),
+			"pull_request_name": fmt.Sprintf("Listing feature %d", i),
+			"author_id":         "ls1",
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+	w = postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-listing-5"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	seen := []string{}
+	cursor := ""
+	for {
+		query := "team_name=listing&status=OPEN&sort=name&order=asc&limit=2"
+		if cursor != "" {
+			query += "&cursor=" + cursor
+		}
+		resp, page := list(query)
+		require.Equal(t, http.StatusOK, resp.Code)
+		for _, pr := range page.PullRequests {
+			assert.Equal(t, models.StatusOpen, pr.Status)
+			assert.Equal(t, []string{"ls2"}, pr.AssignedReviewers)
+			seen = append(seen, pr.PullRequestID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"pr-listing-1", "pr-listing-2", "pr-listing-3", "pr-listing-4"}, seen)
+
+	w, page := list("reviewer_id=ls2&status=MERGED")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, page.PullRequests, 1)
+	assert.Equal(t, "pr-listing-5", page.PullRequests[0].PullRequestID)
+
+	w, page = list("author_id=ls1&q=feature%203")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, page.PullRequests, 1)
+	assert.Equal(t, "pr-listing-3", page.PullRequests[0].PullRequestID)
+
+	w, _ = list("status=UNKNOWN")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w, _ = list("cursor=garbage")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestGetReviewPagination(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'paging'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := postJSON("/team/add", models.Team{
+		TeamName: "paging",
+		Members: []models.TeamMember{
+			{UserID: "pg1", Username: "Author", IsActive: true},
+			{UserID: "pg2", Username: "Reviewer", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	for i := 1; i <= 3; i++ {
+		w = postJSON("/pullRequest/create", map[string]string{
+			"pull_request_id":   fmt.Sprintf("pr-paging-%d", i),
+			"pull_request_name": "Paged",
+			"author_id":         "pg1",
+		})
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+	w = postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-paging-1"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	type reviewPage struct {
+		PullRequests []models.PullRequestShort `json:"pull_requests"`
+		NextCursor   string                    `json:"next_cursor"`
+	}
+	getReview := func(query string) (*httptest.ResponseRecorder, reviewPage) {
+		req, _ := http.NewRequest("GET", "/users/getReview?user_id=pg2&"+query, nil)
+		resp := httptest.NewRecorder()
+		r.ServeHTTP(resp, req)
+		var page reviewPage
+		json.Unmarshal(resp.Body.Bytes(), &page) //nolint:errcheck
+		return resp, page
+	}
+
+	w, page := getReview("limit=2")
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, page.PullRequests, 2)
+	assert.Equal(t, "pr-paging-3", page.PullRequests[0].PullRequestID)
+	assert.Equal(t, "pr-paging-2", page.PullRequests[1].PullRequestID)
+	assert.NotNil(t, page.PullRequests[0].AssignedAt)
+	require.NotEmpty(t, page.NextCursor)
+
+	w, page = getReview("limit=2&cursor=" + page.NextCursor)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, page.PullRequests, 1)
+	assert.Equal(t, "pr-paging-1", page.PullRequests[0].PullRequestID)
+	assert.Empty(t, page.NextCursor)
+
+	w, page = getReview("status=OPEN")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, page.PullRequests, 2)
+
+	w, page = getReview("limit=0")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Len(t, page.PullRequests, 3)
+	assert.Empty(t, page.NextCursor)
+
+	w, _ = getReview("limit=1000")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestAddAndRemoveReviewer(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'manual'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) string {
+		var response models.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+		return response.Error.Code
+	}
+	errorMessage := func(w *httptest.ResponseRecorder) string {
+		var response models.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+		return response.Error.Message
+	}
+
+	w := postJSON("/team/add", models.Team{
+		TeamName: "manual",
+		Members: []models.TeamMember{
+			{UserID: "mn1", Username: "Author", IsActive: true},
+			{UserID: "mn2", Username: "Default", IsActive: true},
+			{UserID: "mn3", Username: "Expert", IsActive: true},
+			{UserID: "mn4", Username: "Away", IsActive: false},
+			{UserID: "mn5", Username: "Vacation", IsActive: true},
+			{UserID: "mn6", Username: "Busy", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	now := time.Now().UTC()
+	w = postJSON("/users/absences", map[string]interface{}{
+		"user_id":   "mn5",
+		"starts_at": now.Add(-time.Hour),
+		"ends_at":   now.Add(24 * time.Hour),
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/users/setMaxOpenReviews", map[string]interface{}{"user_id": "mn6", "max_open_reviews": 0})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = postJSON("/team/settings", map[string]interface{}{"team_name": "manual", "reviewer_count": 1})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-manual-1",
+		"pull_request_name": "Needs an expert",
+		"author_id":         "mn1",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	require.Len(t, created.PR.AssignedReviewers, 1)
+	assigned := created.PR.AssignedReviewers[0]
+	extra := "mn3"
+	if assigned == "mn3" {
+		extra = "mn2"
+	}
+
+	w = postJSON("/pullRequest/addReviewer", map[string]string{"pull_request_id": "pr-manual-1", "user_id": "mn1"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "NO_CANDIDATE", errorCode(w))
+	assert.Equal(t, "author cannot review own PR", errorMessage(w))
+
+	w = postJSON("/pullRequest/addReviewer", map[string]string{"pull_request_id": "pr-manual-1", "user_id": "mn4"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "NO_CANDIDATE", errorCode(w))
+	assert.Equal(t, "reviewer is not active", errorMessage(w))
+
+	w = postJSON("/pullRequest/addReviewer", map[string]string{"pull_request_id": "pr-manual-1", "user_id": assigned})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "NO_CANDIDATE", errorCode(w))
+	assert.Equal(t, "reviewer is already assigned to this PR", errorMessage(w))
+
+	w = postJSON("/pullRequest/addReviewer", map[string]string{"pull_request_id": "pr-manual-1", "user_id": "mn5"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "NO_CANDIDATE", errorCode(w))
+	assert.Equal(t, "reviewer is absent", errorMessage(w))
+
+	w = postJSON("/pullRequest/addReviewer", map[string]string{"pull_request_id": "pr-manual-1", "user_id": "mn6"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "CAPACITY_EXHAUSTED", errorCode(w))
+
+	w = postJSON("/pullRequest/addReviewer", map[string]string{"pull_request_id": "pr-manual-1", "user_id": extra})
+	require.Equal(t, http.StatusOK, w.Code)
+	var updated struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &updated) //nolint:errcheck
+	assert.ElementsMatch(t, []string{assigned, extra}, updated.PR.AssignedReviewers)
+
+	w = postJSON("/pullRequest/removeReviewer", map[string]string{"pull_request_id": "pr-manual-1", "user_id": assigned})
+	require.Equal(t, http.StatusOK, w.Code)
+	json.Unmarshal(w.Body.Bytes(), &updated) //nolint:errcheck
+	assert.Equal(t, []string{extra}, updated.PR.AssignedReviewers)
+
+	w = postJSON("/pullRequest/removeReviewer", map[string]string{"pull_request_id": "pr-manual-1", "user_id": assigned})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "NOT_ASSIGNED", errorCode(w))
+
+	w = postJSON("/pullRequest/merge", map[string]string{"pull_request_id": "pr-manual-1"})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = postJSON("/pullRequest/addReviewer", map[string]string{"pull_request_id": "pr-manual-1", "user_id": assigned})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "PR_MERGED", errorCode(w))
+
+	w = postJSON("/pullRequest/removeReviewer", map[string]string{"pull_request_id": "pr-manual-1", "user_id": extra})
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, "PR_MERGED", errorCode(w))
+}
+
+func TestReassignToChosenReviewer(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name IN ('chosen', 'chosen-other')") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	errorCode := func(w *httptest.ResponseRecorder) string {
+		var response models.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+		return response.Error.Code
+	}
+
+	w := postJSON("/team/add", models.Team{
+		TeamName: "chosen",
+		Members: []models.TeamMember{
+			{UserID: "ch1", Username: "Author", IsActive: true},
+			{UserID: "ch2", Username: "Current", IsActive: true},
+			{UserID: "ch3", Username: "Lead pick", IsActive: true},
+			{UserID: "ch4", Username: "Inactive", IsActive: false},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+	w = postJSON("/team/add", models.Team{
+		TeamName: "chosen-other",
+		Members:  []models.TeamMember{{UserID: "ch5", Username: "Outsider", IsActive: true}},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/team/settings", map[string]interface{}{"team_name": "chosen", "reviewer_count": 1})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-chosen-1",
+		"pull_request_name": "Hand over",
+		"author_id":         "ch1",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	require.Len(t, created.PR.AssignedReviewers, 1)
+	current := created.PR.AssignedReviewers[0]
+	target := "ch3"
+	if current == "ch3" {
+		target = "ch2"
+	}
+
+	cases := []struct {
+		newUserID string
+		status    int
+		code      string
+	}{
+		{"missing-user", http.StatusNotFound, "NOT_FOUND"},
+		{"ch5", http.StatusConflict, "WRONG_TEAM"},
+		{"ch4", http.StatusConflict, "NO_CANDIDATE"},
+		{"ch1", http.StatusConflict, "NO_CANDIDATE"},
+		{current, http.StatusConflict, "NO_CANDIDATE"},
+	}
+	for _, tc := range cases {
+		w = postJSON("/pullRequest/reassign", map[string]string{
+			"pull_request_id": "pr-chosen-1",
+			"old_user_id":     current,
+			"new_user_id":     tc.newUserID,
+		})
+		assert.Equal(t, tc.status, w.Code, tc.newUserID)
+		assert.Equal(t, tc.code, errorCode(w), tc.newUserID)
+	}
+
+	w = postJSON("/pullRequest/reassign", map[string]string{
+		"pull_request_id": "pr-chosen-1",
+		"old_user_id":     current,
+		"new_user_id":     target,
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var response struct {
+		PR         models.PullRequest `json:"pr"`
+		ReplacedBy string             `json:"replaced_by"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+	assert.Equal(t, target, response.ReplacedBy)
+	assert.Equal(t, []string{target}, response.PR.AssignedReviewers)
+}
+
+func TestDeclineReview(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'decline'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	type declineResponse struct {
+		PR         models.PullRequest `json:"pr"`
+		ReplacedBy string             `json:"replaced_by"`
+	}
+
+	w := postJSON("/team/add", models.Team{
+		TeamName: "decline",
+		Members: []models.TeamMember{
+			{UserID: "dc1", Username: "Author", IsActive: true},
+			{UserID: "dc2", Username: "Busy", IsActive: true},
+			{UserID: "dc3", Username: "Free", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/team/settings", map[string]interface{}{"team_name": "decline", "reviewer_count": 1})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	w = postJSON("/pullRequest/create", map[string]string{
+		"pull_request_id":   "pr-decline-1",
+		"pull_request_name": "Nobody wants it",
+		"author_id":         "dc1",
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var created struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &created) //nolint:errcheck
+	require.Len(t, created.PR.AssignedReviewers, 1)
+	first := created.PR.AssignedReviewers[0]
+	second := "dc3"
+	if first == "dc3" {
+		second = "dc2"
+	}
+
+	w = postJSON("/pullRequest/decline", map[string]string{"pull_request_id": "pr-decline-1", "reviewer_id": first})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postJSON("/pullRequest/decline", map[string]string{"pull_request_id": "pr-decline-1", "reviewer_id": first, "category": "BORED", "reason": "overloaded"})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = postJSON("/pullRequest/decline", map[string]string{"pull_request_id": "pr-decline-1", "reviewer_id": first, "category": "OVERLOADED", "reason": "three releases this week"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var response declineResponse
+	json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+	assert.Equal(t, second, response.ReplacedBy)
+	assert.Equal(t, []string{second}, response.PR.AssignedReviewers)
+	assert.Equal(t, []string{first}, response.PR.DeclinedReviewers)
+
+	w = postJSON("/pullRequest/decline", map[string]string{"pull_request_id": "pr-decline-1", "reviewer_id": second, "reason": "overloaded"})
+	require.Equal(t, http.StatusOK, w.Code)
+	response = declineResponse{}
+	json.Unmarshal(w.Body.Bytes(), &response) //nolint:errcheck
+	assert.Empty(t, response.ReplacedBy)
+	assert.Empty(t, response.PR.AssignedReviewers)
+
+	w = postJSON("/pullRequest/decline", map[string]string{"pull_request_id": "pr-decline-1", "reviewer_id": second, "reason": "again"})
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	req, _ := http.NewRequest("GET", "/stats", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var stats models.StatsResponse
+	json.Unmarshal(w.Body.Bytes(), &stats) //nolint:errcheck
+	for _, stat := range stats.UserStats {
+		switch stat.UserID {
+		case first:
+			assert.Equal(t, 1, stat.DeclinedCount)
+			assert.Equal(t, []models.DeclineCategoryStat{{Category: models.DeclineOverloaded, Count: 1}}, stat.DeclineCategories)
+		case second:
+			assert.Equal(t, 1, stat.DeclinedCount)
+			assert.Equal(t, []models.DeclineCategoryStat{{Category: models.DeclineOther, Count: 1}}, stat.DeclineCategories)
+		}
+	}
+}
+
+func TestProblemJSONErrors(t *testing.T) {
+	r := setupRouter(t)
+
+	send := func(method, path, accept string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			json.NewEncoder(&body).Encode(payload) //nolint:errcheck
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		req.Header.Set("X-Request-ID", "req-problem-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("POST", "/pullRequest/create", "application/problem+json", map[string]string{"pull_request_name": "No id"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	var problem models.ProblemDetails
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+	assert.Equal(t, "Bad Request", problem.Title)
+	assert.Equal(t, "INVALID_REQUEST", problem.Code)
+	assert.Equal(t, "req-problem-1", problem.Instance)
+	assert.NotEmpty(t, problem.Type)
+	fields := map[string]string{}
+	for _, violation := range problem.Errors {
+		fields[violation.Field] = violation.Rule
+	}
+	assert.Equal(t, map[string]string{"pull_request_id": "required", "author_id": "required"}, fields)
+
+	w = send("GET", "/pullRequest/list?limit=1000", "application/json, application/problem+json;q=0.5", nil)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	problem = models.ProblemDetails{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	require.Len(t, problem.Errors, 1)
+	assert.Equal(t, "limit", problem.Errors[0].Field)
+	assert.Equal(t, "max", problem.Errors[0].Rule)
+
+	w = send("GET", "/pullRequest/get?pull_request_id=pr-problem-missing", "application/problem+json", nil)
+	require.Equal(t, http.StatusNotFound, w.Code)
+	problem = models.ProblemDetails{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "NOT_FOUND", problem.Code)
+	assert.Equal(t, "PR not found", problem.Detail)
+
+	w = send("POST", "/pullRequest/create", "", map[string]string{"pull_request_name": "No id"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	var legacy models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &legacy))
+	assert.Equal(t, "INVALID_REQUEST", legacy.Error.Code)
+	assert.NotEmpty(t, legacy.Error.Message)
+
+	w = send("POST", "/pullRequest/create", "application/problem+json;q=0", map[string]string{"pull_request_name": "No id"})
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'idempotency'")                     //nolint:errcheck
+	_, _ = db.Exec("DELETE FROM idempotency_keys WHERE idempotency_key LIKE 'idem-test-%'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	postJSON := func(path, key string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if key != "" {
+			req.Header.Set("Idempotency-Key", key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := postJSON("/team/add", "", models.Team{
+		TeamName: "idempotency",
+		Members: []models.TeamMember{
+			{UserID: "id1", Username: "Author", IsActive: true},
+			{UserID: "id2", Username: "First", IsActive: true},
+			{UserID: "id3", Username: "Second", IsActive: true},
+			{UserID: "id4", Username: "Third", IsActive: true},
+		},
+	})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	w = postJSON("/team/settings", "", map[string]interface{}{"team_name": "idempotency", "reviewer_count": 1})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	createReq := map[string]string{
+		"pull_request_id":   "pr-idempotency-1",
+		"pull_request_name": "Retry me",
+		"author_id":         "id1",
+	}
+	first := postJSON("/pullRequest/create", "idem-test-create", createReq)
+	require.Equal(t, http.StatusCreated, first.Code)
+	assert.Empty(t, first.Header().Get("Idempotent-Replayed"))
+
+	retry := postJSON("/pullRequest/create", "idem-test-create", createReq)
+	require.Equal(t, http.StatusCreated, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+
+	w = postJSON("/pullRequest/create", "idem-test-create", map[string]string{
+		"pull_request_id":   "pr-idempotency-2",
+		"pull_request_name": "Other body",
+		"author_id":         "id1",
+	})
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	var errResp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", errResp.Error.Code)
+
+	body, _ := json.Marshal(createReq)
+	req, _ := http.NewRequest("POST", "/pullRequest/create", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/problem+json")
+	req.Header.Set("Idempotency-Key", strings.Repeat("k", 256))
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	var problem models.ProblemDetails
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "INVALID_REQUEST", problem.Code)
+	assert.Equal(t, http.StatusBadRequest, problem.Status)
+
+	req, _ = http.NewRequest("POST", "/pullRequest/create", bytes.NewBuffer([]byte(`{"pull_request_id":"pr-idempotency-2"}`)))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/problem+json")
+	req.Header.Set("Idempotency-Key", "idem-test-create")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	problem = models.ProblemDetails{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", problem.Code)
+	assert.Equal(t, "urn:pr-reviewer:problem:idempotency-key-reused", problem.Type)
+
+	w = postJSON("/pullRequest/create", "", createReq)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var created struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	json.Unmarshal(first.Body.Bytes(), &created) //nolint:errcheck
+	require.Len(t, created.PR.AssignedReviewers, 1)
+	oldReviewer := created.PR.AssignedReviewers[0]
+
+	reassignReq := map[string]string{"pull_request_id": "pr-idempotency-1", "old_user_id": oldReviewer}
+	first = postJSON("/pullRequest/reassign", "idem-test-reassign", reassignReq)
+	require.Equal(t, http.StatusOK, first.Code)
+	retry = postJSON("/pullRequest/reassign", "idem-test-reassign", reassignReq)
+	require.Equal(t, http.StatusOK, retry.Code)
+	assert.Equal(t, "true", retry.Header().Get("Idempotent-Replayed"))
+	assert.JSONEq(t, first.Body.String(), retry.Body.String())
+
+	var reassigned struct {
+		ReplacedBy string `json:"replaced_by"`
+	}
+	json.Unmarshal(first.Body.Bytes(), &reassigned) //nolint:errcheck
+
+	req, _ = http.NewRequest("GET", "/pullRequest/get?pull_request_id=pr-idempotency-1", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var details struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &details) //nolint:errcheck
+	assert.Equal(t, []string{reassigned.ReplacedBy}, details.PR.AssignedReviewers)
+	assert.Len(t, details.PR.ReassignmentHistory, 1)
+}
+
+func TestCanceledRequestStopsQueries(t *testing.T) {
+	r := setupRouter(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", "/pullRequest/list?limit=1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, 499, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestConcurrentCreateMergeReassign(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	_, _ = db.Exec("DELETE FROM teams WHERE team_name = 'concurrency'") //nolint:errcheck
+
+	r := setupRouter(t)
+
+	post := func(path string, payload interface{}, adminToken string) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if adminToken != "" {
+			req.Header.Set("X-Admin-Token", adminToken)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	concurrently := func(n int, fn func(i int) *httptest.ResponseRecorder) ([]*httptest.ResponseRecorder, map[int]int) {
+		responses := make([]*httptest.ResponseRecorder, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				responses[i] = fn(i)
+			}(i)
+		}
+		wg.Wait()
+
+		counts := map[int]int{}
+		for _, resp := range responses {
+			counts[resp.Code]++
+		}
+		return responses, counts
+	}
+	getPR := func(prID string) models.PullRequest {
+		req, _ := http.NewRequest("GET", "/pullRequest/get?pull_request_id="+prID, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			PR models.PullRequest `json:"pr"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.PR
+	}
+
+	members := []models.TeamMember{{UserID: "cc0", Username: "Author", IsActive: true}}
+	for i := 1; i <= 8; i++ {
+		members = append(members, models.TeamMember{UserID: fmt.Sprintf("cc%d", i), Username: fmt.Sprintf("Member %d", i), IsActive: true})
+	}
+	w := post("/team/add", models.Team{TeamName: "concurrency", Members: members}, "")
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	const workers = 10
+	createReq := map[string]string{
+		"pull_request_id":   "pr-concurrency-1",
+		"pull_request_name": "Race",
+		"author_id":         "cc0",
+	}
+	responses, counts := concurrently(workers, func(int) *httptest.ResponseRecorder {
+		return post("/pullRequest/create", createReq, "")
+	})
+	assert.Equal(t, map[int]int{http.StatusCreated: 1, http.StatusConflict: workers - 1}, counts)
+	for _, resp := range responses {
+		if resp.Code == http.StatusConflict {
+			var errResp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errResp))
+			assert.Equal(t, "PR_EXISTS", errResp.Error.Code)
+		}
+	}
+
+	pr := getPR("pr-concurrency-1")
+	require.Len(t, pr.AssignedReviewers, 2)
+
+	oldReviewer := pr.AssignedReviewers[0]
+	_, counts = concurrently(workers, func(int) *httptest.ResponseRecorder {
+		return post("/pullRequest/reassign", map[string]string{"pull_request_id": "pr-concurrency-1", "old_user_id": oldReviewer}, "")
+	})
+	assert.Equal(t, map[int]int{http.StatusOK: 1, http.StatusConflict: workers - 1}, counts)
+
+	for round := 0; round < 5; round++ {
+		assigned := getPR("pr-concurrency-1").AssignedReviewers
+		require.Len(t, assigned, 2)
+		_, counts = concurrently(len(assigned), func(i int) *httptest.ResponseRecorder {
+			return post("/pullRequest/reassign", map[string]string{"pull_request_id": "pr-concurrency-1", "old_user_id": assigned[i]}, "")
+		})
+		assert.Equal(t, map[int]int{http.StatusOK: 2}, counts)
+
+		pr = getPR("pr-concurrency-1")
+		require.Len(t, pr.AssignedReviewers, 2)
+		assert.NotEqual(t, pr.AssignedReviewers[0], pr.AssignedReviewers[1])
+		assert.NotContains(t, pr.AssignedReviewers, "cc0")
+	}
+
+	decliners := pr.AssignedReviewers
+	decline := func(reviewerID string) *httptest.ResponseRecorder {
+		return post("/pullRequest/decline", map[string]string{
+			"pull_request_id": "pr-concurrency-1",
+			"reviewer_id":     reviewerID,
+			"category":        "OVERLOADED",
+			"reason":          "race",
+		}, "")
+	}
+	_, counts = concurrently(len(decliners), func(i int) *httptest.ResponseRecorder {
+		return decline(decliners[i])
+	})
+	assert.Equal(t, map[int]int{http.StatusOK: 2}, counts)
+
+	pr = getPR("pr-concurrency-1")
+	require.Len(t, pr.AssignedReviewers, 2)
+	assert.NotEqual(t, pr.AssignedReviewers[0], pr.AssignedReviewers[1])
+	assert.NotContains(t, pr.AssignedReviewers, "cc0")
+	for _, decliner := range decliners {
+		assert.NotContains(t, pr.AssignedReviewers, decliner)
+	}
+
+	decliner := pr.AssignedReviewers[0]
+	responses, counts = concurrently(workers, func(int) *httptest.ResponseRecorder {
+		return decline(decliner)
+	})
+	assert.Equal(t, map[int]int{http.StatusOK: 1, http.StatusConflict: workers - 1}, counts)
+	for _, resp := range responses {
+		if resp.Code == http.StatusConflict {
+			var errResp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errResp))
+			assert.Equal(t, "NOT_ASSIGNED", errResp.Error.Code)
+		}
+	}
+	pr = getPR("pr-concurrency-1")
+	require.Len(t, pr.AssignedReviewers, 2)
+	assert.NotContains(t, pr.AssignedReviewers, decliner)
+	assert.NotEqual(t, pr.AssignedReviewers[0], pr.AssignedReviewers[1])
+
+	var extra string
+	for i := 1; i <= 8; i++ {
+		if candidate := fmt.Sprintf("cc%d", i); !slices.Contains(pr.AssignedReviewers, candidate) {
+			extra = candidate
+			break
+		}
+	}
+	addReq := map[string]string{"pull_request_id": "pr-concurrency-1", "user_id": extra}
+	responses, counts = concurrently(workers, func(int) *httptest.ResponseRecorder {
+		return post("/pullRequest/addReviewer", addReq, "")
+	})
+	assert.Equal(t, map[int]int{http.StatusOK: 1, http.StatusConflict: workers - 1}, counts)
+	for _, resp := range responses {
+		assert.NotEqual(t, http.StatusInternalServerError, resp.Code)
+	}
+	pr = getPR("pr-concurrency-1")
+	assert.Len(t, pr.AssignedReviewers, 3)
+	assert.Contains(t, pr.AssignedReviewers, extra)
+
+	responses, counts = concurrently(workers, func(int) *httptest.ResponseRecorder {
+		return post("/pullRequest/removeReviewer", addReq, "")
+	})
+	assert.Equal(t, map[int]int{http.StatusOK: 1, http.StatusConflict: workers - 1}, counts)
+	for _, resp := range responses {
+		if resp.Code == http.StatusConflict {
+			var errResp models.ErrorResponse
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &errResp))
+			assert.Equal(t, "NOT_ASSIGNED", errResp.Error.Code)
+		}
+	}
+	assert.NotContains(t, getPR("pr-concurrency-1").AssignedReviewers, extra)
+
+	responses, counts = concurrently(workers, func(int) *httptest.ResponseRecorder {
+		return post("/pullRequest/merge", map[string]interface{}{"pull_request_id": "pr-concurrency-1", "force": true}, testAdminToken)
+	})
+	assert.Equal(t, map[int]int{http.StatusOK: workers}, counts)
+
+	merged := getPR("pr-concurrency-1")
+	require.NotNil(t, merged.MergedAt)
+	for _, resp := range responses {
+		var response struct {
+			PR models.PullRequest `json:"pr"`
+		}
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &response))
+		require.NotNil(t, response.PR.MergedAt)
+		assert.True(t, merged.MergedAt.Equal(*response.PR.MergedAt))
+	}
+
+	_, counts = concurrently(workers, func(i int) *httptest.ResponseRecorder {
+		if i%2 == 0 {
+			return post("/pullRequest/addReviewer", addReq, "")
+		}
+		return post("/pullRequest/removeReviewer", map[string]string{"pull_request_id": "pr-concurrency-1", "user_id": merged.AssignedReviewers[0]}, "")
+	})
+	assert.Equal(t, map[int]int{http.StatusConflict: workers}, counts)
+	assert.Equal(t, merged.AssignedReviewers, getPR("pr-concurrency-1").AssignedReviewers)
+}
+
+func TestTxManagerRollbackAndNesting(t *testing.T) {
+	setupTestDB(t)
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	for _, name := range []string{"uow-rollback", "uow-outer", "uow-inner"} {
+		_, _ = db.Exec("DELETE FROM users WHERE team_name = $1", name) //nolint:errcheck
+		_, _ = db.Exec("DELETE FROM teams WHERE team_name = $1", name) //nolint:errcheck
+	}
+
+	ctx := context.Background()
+	txManager := repository.NewTxManager(db)
+	teamRepo := repository.NewTeamRepository(db)
+	userRepo := repository.NewUserRepository(db)
+	errAbort := fmt.Errorf("abort")
+
+	err = txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if createErr := teamRepo.CreateTeam(ctx, &models.Team{
+			TeamName: "uow-rollback",
+			Members:  []models.TeamMember{{UserID: "uow1", Username: "Rolled back", IsActive: true}},
+		}); createErr != nil {
+			return createErr
+		}
+
+		exists, existsErr := teamRepo.TeamExists(ctx, "uow-rollback")
+		require.NoError(t, existsErr)
+		assert.True(t, exists)
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	exists, err := teamRepo.TeamExists(ctx, "uow-rollback")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	_, err = userRepo.GetUser(ctx, "uow1")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if createErr := teamRepo.CreateTeam(ctx, &models.Team{
+			TeamName: "uow-outer",
+			Members:  []models.TeamMember{{UserID: "uow2", Username: "Outer", IsActive: true}},
+		}); createErr != nil {
+			return createErr
+		}
+
+		innerErr := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if createErr := teamRepo.CreateTeam(ctx, &models.Team{TeamName: "uow-inner"}); createErr != nil {
+				return createErr
+			}
+			if _, setErr := userRepo.SetIsActive(ctx, "uow2", false); setErr != nil {
+				return setErr
+			}
+			return errAbort
+		})
+		assert.ErrorIs(t, innerErr, errAbort)
+		return nil
+	})
+	require.NoError(t, err)
+
+	exists, err = teamRepo.TeamExists(ctx, "uow-outer")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = teamRepo.TeamExists(ctx, "uow-inner")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	user, err := userRepo.GetUser(ctx, "uow2")
+	require.NoError(t, err)
+	assert.True(t, user.IsActive)
+}
@@ -0,0 +1,277 @@
+package test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/avito/pr-reviewer-service/internal/middleware"
+	"github.com/avito/pr-reviewer-service/internal/models"
+	"github.com/avito/pr-reviewer-service/internal/repository"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupMemoryRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	store := repository.NewMemoryStore()
+
+	return newRouter(
+		nil,
+		repository.NewMemoryTeamRepository(store),
+		repository.NewMemoryUserRepository(store),
+		repository.NewMemoryPullRequestRepository(store),
+		repository.NewMemoryAbsenceRepository(store),
+		repository.NewMemoryTxManager(store),
+		repository.NewMemoryIdempotencyRepository(store),
+	)
+}
+
+func TestMemoryStoragePRLifecycle(t *testing.T) {
+	r := setupMemoryRouter(t)
+
+	request := func(method, path string, payload interface{}, headers map[string]string) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			require.NoError(t, json.NewEncoder(&body).Encode(payload))
+		}
+		req, _ := http.NewRequest(method, path, &body)
+		req.Header.Set("Content-Type", "application/json")
+		for key, value := range headers {
+			req.Header.Set(key, value)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	decodePR := func(w *httptest.ResponseRecorder) models.PullRequest {
+		var response struct {
+			PR models.PullRequest `json:"pr"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.PR
+	}
+
+	w := request("POST", "/team/add", models.Team{TeamName: "memory", Members: []models.TeamMember{
+		{UserID: "mem1", Username: "Author", IsActive: true},
+		{UserID: "mem2", Username: "Bob", IsActive: true},
+		{UserID: "mem3", Username: "Carol", IsActive: true},
+		{UserID: "mem4", Username: "Dave", IsActive: true},
+	}}, nil)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	createReq := map[string]string{"pull_request_id": "mem-pr-1", "pull_request_name": "Memory storage", "author_id": "mem1"}
+	w = request("POST", "/pullRequest/create", createReq, map[string]string{middleware.IdempotencyKeyHeader: "mem-create-1"})
+	require.Equal(t, http.StatusCreated, w.Code)
+	pr := decodePR(w)
+	assert.Equal(t, "memory", pr.AuthorTeam)
+	require.Len(t, pr.AssignedReviewers, 2)
+	assert.NotContains(t, pr.AssignedReviewers, "mem1")
+
+	w = request("POST", "/pullRequest/create", createReq, map[string]string{middleware.IdempotencyKeyHeader: "mem-create-1"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "true", w.Header().Get(middleware.IdempotentReplayedHeader))
+
+	w = request("POST", "/pullRequest/create", createReq, nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	for i := 2; i <= 3; i++ {
+		w = request("POST", "/pullRequest/create", map[string]interface{}{
+			"pull_request_id":   fmt.Sprintf("mem-pr-%d", i),
+			"pull_request_name": fmt.Sprintf("Draft %d", i),
+			"author_id":         "mem1",
+			"draft":             true,
+		}, nil)
+		require.Equal(t, http.StatusCreated, w.Code)
+	}
+
+	oldReviewer := pr.AssignedReviewers[0]
+	w = request("POST", "/pullRequest/reassign", map[string]string{"pull_request_id": "mem-pr-1", "old_user_id": oldReviewer}, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	pr = decodePR(w)
+	assert.NotContains(t, pr.AssignedReviewers, oldReviewer)
+	require.Len(t, pr.AssignedReviewers, 2)
+
+	w = request("POST", "/users/setIsActive", map[string]interface{}{"user_id": pr.AssignedReviewers[0], "is_active": false, "reassign_reviews": true}, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = request("GET", "/pullRequest/get?pull_request_id=mem-pr-1", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	pr = decodePR(w)
+	require.Len(t, pr.AssignedReviewers, 2)
+
+	var page models.PRListPage
+	w = request("GET", "/pullRequest/list?author_id=mem1&sort=name&order=asc&limit=2", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.PullRequests, 2)
+	assert.Equal(t, "mem-pr-2", page.PullRequests[0].PullRequestID)
+	assert.Equal(t, "mem-pr-3", page.PullRequests[1].PullRequestID)
+	require.NotEmpty(t, page.NextCursor)
+
+	w = request("GET", "/pullRequest/list?author_id=mem1&sort=name&order=asc&limit=2&cursor="+page.NextCursor, nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	page = models.PRListPage{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &page))
+	require.Len(t, page.PullRequests, 1)
+	assert.Equal(t, "mem-pr-1", page.PullRequests[0].PullRequestID)
+	assert.Empty(t, page.NextCursor)
+
+	w = request("GET", "/users/getReview?user_id="+pr.AssignedReviewers[0], nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), "mem-pr-1")
+
+	for _, reviewerID := range pr.AssignedReviewers {
+		w = request("POST", "/pullRequest/review", map[string]string{"pull_request_id": "mem-pr-1", "reviewer_id": reviewerID, "state": "APPROVED"}, nil)
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+	w = request("POST", "/pullRequest/merge", map[string]string{"pull_request_id": "mem-pr-1"}, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	merged := decodePR(w)
+	assert.Equal(t, models.StatusMerged, merged.Status)
+	require.NotNil(t, merged.MergedAt)
+
+	w = request("GET", "/stats", nil, nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var stats models.StatsResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &stats))
+	assert.Equal(t, models.PRStat{TotalPRs: 3, DraftPRs: 2, MergedPRs: 1}, stats.PRStats)
+	assert.Len(t, stats.UserStats, 4)
+
+	w = request("GET", "/health", nil, nil)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, w.Body.String(), "database")
+}
+
+func TestMemoryStorageConcurrentCreateAndReassign(t *testing.T) {
+	r := setupMemoryRouter(t)
+
+	post := func(path string, payload interface{}) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(payload)
+		req, _ := http.NewRequest("POST", path, bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	concurrently := func(n int, fn func() *httptest.ResponseRecorder) map[int]int {
+		codes := make([]int, n)
+		var wg sync.WaitGroup
+		for i := 0; i < n; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				codes[i] = fn().Code
+			}(i)
+		}
+		wg.Wait()
+
+		counts := map[int]int{}
+		for _, code := range codes {
+			counts[code]++
+		}
+		return counts
+	}
+
+	members := []models.TeamMember{{UserID: "mc0", Username: "Author", IsActive: true}}
+	for i := 1; i <= 6; i++ {
+		members = append(members, models.TeamMember{UserID: fmt.Sprintf("mc%d", i), Username: fmt.Sprintf("Member %d", i), IsActive: true})
+	}
+	w := post("/team/add", models.Team{TeamName: "memory-concurrency", Members: members})
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	const workers = 10
+	counts := concurrently(workers, func() *httptest.ResponseRecorder {
+		return post("/pullRequest/create", map[string]string{"pull_request_id": "mc-pr", "pull_request_name": "Race", "author_id": "mc0"})
+	})
+	assert.Equal(t, map[int]int{http.StatusCreated: 1, http.StatusConflict: workers - 1}, counts)
+
+	req, _ := http.NewRequest("GET", "/pullRequest/get?pull_request_id=mc-pr", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	var response struct {
+		PR models.PullRequest `json:"pr"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.Len(t, response.PR.AssignedReviewers, 2)
+
+	counts = concurrently(workers, func() *httptest.ResponseRecorder {
+		return post("/pullRequest/reassign", map[string]string{"pull_request_id": "mc-pr", "old_user_id": response.PR.AssignedReviewers[0]})
+	})
+	assert.Equal(t, map[int]int{http.StatusOK: 1, http.StatusConflict: workers - 1}, counts)
+}
+
+func TestMemoryTxManagerRollbackAndNesting(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryStore()
+	txManager := repository.NewMemoryTxManager(store)
+	teamRepo := repository.NewMemoryTeamRepository(store)
+	userRepo := repository.NewMemoryUserRepository(store)
+	errAbort := fmt.Errorf("abort")
+
+	err := txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if createErr := teamRepo.CreateTeam(ctx, &models.Team{
+			TeamName: "uow-rollback",
+			Members:  []models.TeamMember{{UserID: "uow1", Username: "Rolled back", IsActive: true}},
+		}); createErr != nil {
+			return createErr
+		}
+
+		exists, existsErr := teamRepo.TeamExists(ctx, "uow-rollback")
+		require.NoError(t, existsErr)
+		assert.True(t, exists)
+		return errAbort
+	})
+	assert.ErrorIs(t, err, errAbort)
+
+	exists, err := teamRepo.TeamExists(ctx, "uow-rollback")
+	require.NoError(t, err)
+	assert.False(t, exists)
+	_, err = userRepo.GetUser(ctx, "uow1")
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	err = txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if createErr := teamRepo.CreateTeam(ctx, &models.Team{
+			TeamName: "uow-outer",
+			Members:  []models.TeamMember{{UserID: "uow2", Username: "Outer", IsActive: true}},
+		}); createErr != nil {
+			return createErr
+		}
+
+		innerErr := txManager.WithinTx(ctx, func(ctx context.Context) error {
+			if createErr := teamRepo.CreateTeam(ctx, &models.Team{TeamName: "uow-inner"}); createErr != nil {
+				return createErr
+			}
+			if _, setErr := userRepo.SetIsActive(ctx, "uow2", false); setErr != nil {
+				return setErr
+			}
+			return errAbort
+		})
+		assert.ErrorIs(t, innerErr, errAbort)
+		return nil
+	})
+	require.NoError(t, err)
+
+	exists, err = teamRepo.TeamExists(ctx, "uow-outer")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	exists, err = teamRepo.TeamExists(ctx, "uow-inner")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	user, err := userRepo.GetUser(ctx, "uow2")
+	require.NoError(t, err)
+	assert.True(t, user.IsActive)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = userRepo.GetUser(canceled, "uow2")
+	assert.ErrorIs(t, err, context.Canceled)
+}
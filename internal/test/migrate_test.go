@@ -0,0 +1,154 @@
+package test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/avito/pr-reviewer-service/internal/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func openMigrationTestDB(t *testing.T) *sql.DB {
+	t.Helper()
+	t.Setenv("DB_DRIVER", database.DriverSQLite)
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "migrations.db"))
+
+	db, err := database.NewDB()
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() }) //nolint:errcheck
+	return db
+}
+
+func migrationFS(firstUp string) fstest.MapFS {
+	return fstest.MapFS{
+		"000001_widgets.up.sql":   {Data: []byte(firstUp)},
+		"000001_widgets.down.sql": {Data: []byte("DROP TABLE widgets;")},
+		"000002_gadgets.up.sql":   {Data: []byte("CREATE TABLE gadgets (id INTEGER PRIMARY KEY);")},
+		"000002_gadgets.down.sql": {Data: []byte("DROP TABLE gadgets;")},
+		"README.md":               {Data: []byte("ignored")},
+	}
+}
+
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	t.Helper()
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = $1`, table).Scan(&count)
+	require.NoError(t, err)
+	return count > 0
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	for _, driver := range []string{database.DriverPostgres, database.DriverSQLite} {
+		fsys, err := database.EmbeddedMigrations(driver)
+		require.NoError(t, err)
+		migrations, err := database.LoadMigrations(fsys)
+		require.NoError(t, err)
+		require.NotEmpty(t, migrations, driver)
+
+		for i, migration := range migrations {
+			assert.Equal(t, int64(i+1), migration.Version, driver)
+			assert.NotEmpty(t, migration.Down, "%s migration %d has no down file", driver, migration.Version)
+			assert.Len(t, migration.Checksum, 64)
+		}
+	}
+
+	_, err := database.EmbeddedMigrations("mysql")
+	assert.Error(t, err)
+}
+
+func TestMigratorLifecycle(t *testing.T) {
+	ctx := context.Background()
+	db := openMigrationTestDB(t)
+
+	migrator, err := database.NewMigrator(db, database.DriverSQLite, migrationFS("CREATE TABLE widgets (id INTEGER PRIMARY KEY);"))
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 2)
+	assert.Equal(t, "widgets", applied[0].Name)
+	assert.True(t, tableExists(t, db, "widgets"))
+	assert.True(t, tableExists(t, db, "gadgets"))
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	for _, status := range statuses {
+		assert.True(t, status.Applied)
+		assert.False(t, status.Modified)
+		assert.NotNil(t, status.AppliedAt)
+	}
+
+	modified, err := database.NewMigrator(db, database.DriverSQLite, migrationFS("CREATE TABLE widgets (id INTEGER PRIMARY KEY, name TEXT);"))
+	require.NoError(t, err)
+	_, err = modified.Up(ctx)
+	assert.ErrorIs(t, err, database.ErrChecksumMismatch)
+	statuses, err = modified.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Modified)
+
+	require.NoError(t, modified.Force(ctx, 2))
+	_, err = modified.Up(ctx)
+	require.NoError(t, err)
+	assert.ErrorIs(t, modified.Force(ctx, 7), database.ErrUnknownMigration)
+
+	reverted, err := modified.Down(ctx, 1)
+	require.NoError(t, err)
+	require.Len(t, reverted, 1)
+	assert.Equal(t, int64(2), reverted[0].Version)
+	assert.False(t, tableExists(t, db, "gadgets"))
+	assert.True(t, tableExists(t, db, "widgets"))
+
+	statuses, err = modified.Status(ctx)
+	require.NoError(t, err)
+	assert.True(t, statuses[0].Applied)
+	assert.False(t, statuses[1].Applied)
+
+	reverted, err = modified.Down(ctx, 10)
+	require.NoError(t, err)
+	assert.Len(t, reverted, 1)
+	assert.False(t, tableExists(t, db, "widgets"))
+
+	applied, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, 2)
+}
+
+func TestMigratorAdoptsGolangMigrateTable(t *testing.T) {
+	ctx := context.Background()
+	db := openMigrationTestDB(t)
+
+	_, err := db.Exec(`
+		CREATE TABLE schema_migrations (version BIGINT PRIMARY KEY, dirty BOOLEAN NOT NULL);
+		INSERT INTO schema_migrations (version, dirty) VALUES (1, true);
+		CREATE TABLE widgets (id INTEGER PRIMARY KEY);`)
+	require.NoError(t, err)
+
+	migrator, err := database.NewMigrator(db, database.DriverSQLite, migrationFS("CREATE TABLE widgets (id INTEGER PRIMARY KEY);"))
+	require.NoError(t, err)
+
+	_, err = migrator.Up(ctx)
+	assert.ErrorIs(t, err, database.ErrDirtyMigration)
+
+	_, err = db.Exec(`UPDATE schema_migrations SET dirty = false`)
+	require.NoError(t, err)
+
+	applied, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, applied, 1)
+	assert.Equal(t, int64(2), applied[0].Version)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	assert.True(t, statuses[0].Applied)
+	assert.True(t, statuses[1].Applied)
+}
@@ -0,0 +1,95 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/avito/pr-reviewer-service/internal/models"
+	"github.com/avito/pr-reviewer-service/internal/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func simulateAssignments(selector service.ReviewerSelector, userIDs []string, initial map[string]int, rounds, perPR int) map[string]int {
+	loads := make(map[string]int, len(userIDs))
+	for _, userID := range userIDs {
+		loads[userID] = initial[userID]
+	}
+
+	for i := 0; i < rounds; i++ {
+		candidates := make([]service.ReviewerCandidate, 0, len(userIDs))
+		for _, userID := range userIDs {
+			candidates = append(candidates, service.ReviewerCandidate{
+				User: models.User{UserID: userID, TeamName: "team", IsActive: true},
+				Load: loads[userID],
+			})
+		}
+		for _, userID := range selector.Select("team", candidates, perPR) {
+			loads[userID]++
+		}
+	}
+	return loads
+}
+
+func loadSpread(loads map[string]int) int {
+	lowest, highest := -1, 0
+	for _, load := range loads {
+		if lowest == -1 || load < lowest {
+			lowest = load
+		}
+		if load > highest {
+			highest = load
+		}
+	}
+	return highest - lowest
+}
+
+func TestLeastLoadedSelectorEvensOutWorkload(t *testing.T) {
+	users := []string{"u1", "u2", "u3", "u4", "u5"}
+	loads := simulateAssignments(&service.LeastLoadedSelector{}, users, nil, 37, 2)
+
+	assert.LessOrEqual(t, loadSpread(loads), 1)
+}
+
+func TestLeastLoadedSelectorCatchesUpUnderloadedReviewer(t *testing.T) {
+	users := []string{"u1", "u2", "u3"}
+	loads := simulateAssignments(&service.LeastLoadedSelector{}, users, map[string]int{"u1": 3, "u2": 3}, 3, 1)
+
+	assert.Equal(t, map[string]int{"u1": 3, "u2": 3, "u3": 3}, loads)
+}
+
+func TestLeastLoadedSelectorBreaksTiesRandomly(t *testing.T) {
+	users := []string{"u1", "u2", "u3", "u4"}
+	candidates := make([]service.ReviewerCandidate, 0, len(users))
+	for _, userID := range users {
+		candidates = append(candidates, service.ReviewerCandidate{User: models.User{UserID: userID}})
+	}
+
+	picked := make(map[string]bool)
+	selector := &service.LeastLoadedSelector{}
+	for i := 0; i < 200; i++ {
+		selected := selector.Select("team", candidates, 1)
+		require.Len(t, selected, 1)
+		picked[selected[0]] = true
+	}
+
+	assert.Len(t, picked, len(users))
+}
+
+func TestSelectorsNeverExceedCandidates(t *testing.T) {
+	candidates := []service.ReviewerCandidate{
+		{User: models.User{UserID: "u1"}},
+		{User: models.User{UserID: "u2"}, Load: 4},
+	}
+	selectors := service.NewReviewerSelectors(service.StrategyLeastLoaded, nil)
+
+	for _, strategy := range []service.SelectionStrategy{
+		service.StrategyRandom,
+		service.StrategyRoundRobin,
+		service.StrategyLeastLoaded,
+		service.StrategyWeightedRandom,
+	} {
+		selected := selectors.Get(strategy).Select("team", candidates, 3)
+		assert.ElementsMatch(t, []string{"u1", "u2"}, selected, string(strategy))
+		assert.Empty(t, selectors.Get(strategy).Select("team", nil, 2), string(strategy))
+	}
+}
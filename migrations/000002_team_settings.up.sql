@@ -0,0 +1,8 @@
+CREATE TABLE team_settings (
+    team_name VARCHAR(255) PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE,
+    reviewer_count INTEGER NOT NULL DEFAULT 2 CHECK (reviewer_count >= 0),
+    selection_strategy VARCHAR(32) NOT NULL DEFAULT '',
+    min_approvals INTEGER NOT NULL DEFAULT 0 CHECK (min_approvals >= 0),
+    sla_hours INTEGER NOT NULL DEFAULT 0 CHECK (sla_hours >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
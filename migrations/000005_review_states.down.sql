@@ -0,0 +1,3 @@
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS reviewed_at,
+    DROP COLUMN IF EXISTS review_state;
@@ -0,0 +1,4 @@
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN review_state VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (review_state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    ADD COLUMN reviewed_at TIMESTAMP;
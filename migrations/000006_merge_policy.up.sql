@@ -0,0 +1,3 @@
+ALTER TABLE team_settings
+    ADD COLUMN block_on_changes_requested BOOLEAN NOT NULL DEFAULT true,
+    ADD COLUMN require_all_approved BOOLEAN NOT NULL DEFAULT false;
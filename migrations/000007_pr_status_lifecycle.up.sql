@@ -0,0 +1,4 @@
+ALTER TABLE pull_requests DROP CONSTRAINT IF EXISTS pull_requests_status_check;
+ALTER TABLE pull_requests
+    ADD CONSTRAINT pull_requests_status_check CHECK (status IN ('DRAFT', 'OPEN', 'CLOSED', 'REOPENED', 'MERGED')),
+    ADD COLUMN closed_at TIMESTAMP;
@@ -0,0 +1,10 @@
+CREATE TABLE pull_request_reviewer_changes (
+    change_id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    old_reviewer_id VARCHAR(255),
+    new_reviewer_id VARCHAR(255),
+    reason VARCHAR(20) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_pull_request_reviewer_changes_pr_id ON pull_request_reviewer_changes(pull_request_id, changed_at);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_pull_request_reviewers_reviewer_pr;
+DROP INDEX IF EXISTS idx_pull_requests_name_trgm;
+DROP INDEX IF EXISTS idx_pull_requests_merged_at;
+DROP INDEX IF EXISTS idx_pull_requests_status_created;
+DROP INDEX IF EXISTS idx_pull_requests_author_created;
+DROP INDEX IF EXISTS idx_pull_requests_name_id;
+DROP INDEX IF EXISTS idx_pull_requests_created_at_id;
@@ -0,0 +1,9 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_pull_requests_created_at_id ON pull_requests(created_at, pull_request_id);
+CREATE INDEX idx_pull_requests_name_id ON pull_requests(pull_request_name, pull_request_id);
+CREATE INDEX idx_pull_requests_author_created ON pull_requests(author_id, created_at);
+CREATE INDEX idx_pull_requests_status_created ON pull_requests(status, created_at);
+CREATE INDEX idx_pull_requests_merged_at ON pull_requests(merged_at) WHERE merged_at IS NOT NULL;
+CREATE INDEX idx_pull_requests_name_trgm ON pull_requests USING GIN (pull_request_name gin_trgm_ops);
+CREATE INDEX idx_pull_request_reviewers_reviewer_pr ON pull_request_reviewers(reviewer_id, pull_request_id);
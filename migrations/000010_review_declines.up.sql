@@ -0,0 +1,10 @@
+CREATE TABLE pull_request_declines (
+    decline_id BIGSERIAL PRIMARY KEY,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    declined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_pull_request_declines_pr_id ON pull_request_declines(pull_request_id);
+CREATE INDEX idx_pull_request_declines_reviewer_id ON pull_request_declines(reviewer_id);
@@ -0,0 +1,2 @@
+ALTER TABLE pull_request_declines
+    DROP COLUMN IF EXISTS category;
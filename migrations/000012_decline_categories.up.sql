@@ -0,0 +1,3 @@
+ALTER TABLE pull_request_declines
+    ADD COLUMN category VARCHAR(32) NOT NULL DEFAULT 'OTHER'
+        CHECK (category IN ('OVERLOADED', 'OUT_OF_CONTEXT', 'CONFLICT_OF_INTEREST', 'OTHER'));
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS idempotency_keys;
+DROP TABLE IF EXISTS pull_request_declines;
+DROP TABLE IF EXISTS pull_request_reviewer_changes;
+DROP TABLE IF EXISTS pull_request_reviewers;
+DROP TABLE IF EXISTS pull_requests;
+DROP TABLE IF EXISTS user_absences;
+DROP TABLE IF EXISTS team_settings;
+DROP TABLE IF EXISTS users;
+DROP TABLE IF EXISTS teams;
@@ -0,0 +1,100 @@
+CREATE TABLE teams (
+    team_name VARCHAR(255) PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE users (
+    user_id VARCHAR(255) PRIMARY KEY,
+    username VARCHAR(255) NOT NULL,
+    team_name VARCHAR(255) NOT NULL REFERENCES teams(team_name) ON DELETE CASCADE,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    max_open_reviews INTEGER CHECK (max_open_reviews IS NULL OR max_open_reviews >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE team_settings (
+    team_name VARCHAR(255) PRIMARY KEY REFERENCES teams(team_name) ON DELETE CASCADE,
+    reviewer_count INTEGER NOT NULL DEFAULT 2 CHECK (reviewer_count >= 0),
+    selection_strategy VARCHAR(32) NOT NULL DEFAULT '',
+    min_approvals INTEGER NOT NULL DEFAULT 0 CHECK (min_approvals >= 0),
+    block_on_changes_requested BOOLEAN NOT NULL DEFAULT true,
+    require_all_approved BOOLEAN NOT NULL DEFAULT false,
+    sla_hours INTEGER NOT NULL DEFAULT 0 CHECK (sla_hours >= 0),
+    updated_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE user_absences (
+    absence_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE pull_requests (
+    pull_request_id VARCHAR(255) PRIMARY KEY,
+    pull_request_name VARCHAR(255) NOT NULL,
+    author_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN' CHECK (status IN ('DRAFT', 'OPEN', 'CLOSED', 'REOPENED', 'MERGED')),
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    merged_at TIMESTAMP,
+    closed_at TIMESTAMP
+);
+
+CREATE TABLE pull_request_reviewers (
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    assigned_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    review_state VARCHAR(20) NOT NULL DEFAULT 'PENDING'
+        CHECK (review_state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    reviewed_at TIMESTAMP,
+    PRIMARY KEY (pull_request_id, reviewer_id)
+);
+
+CREATE TABLE pull_request_reviewer_changes (
+    change_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    old_reviewer_id VARCHAR(255),
+    new_reviewer_id VARCHAR(255),
+    reason VARCHAR(20) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE pull_request_declines (
+    decline_id INTEGER PRIMARY KEY AUTOINCREMENT,
+    pull_request_id VARCHAR(255) NOT NULL REFERENCES pull_requests(pull_request_id) ON DELETE CASCADE,
+    reviewer_id VARCHAR(255) NOT NULL REFERENCES users(user_id) ON DELETE CASCADE,
+    reason TEXT NOT NULL,
+    declined_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+
+CREATE TABLE idempotency_keys (
+    idempotency_key VARCHAR(255) PRIMARY KEY,
+    request_hash CHAR(64) NOT NULL,
+    status_code INTEGER,
+    content_type VARCHAR(255),
+    response_body BLOB,
+    created_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    expires_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_users_team_name ON users(team_name);
+CREATE INDEX idx_users_is_active ON users(is_active);
+CREATE INDEX idx_user_absences_user_id_window ON user_absences(user_id, starts_at, ends_at);
+CREATE INDEX idx_pull_requests_author_id ON pull_requests(author_id);
+CREATE INDEX idx_pull_requests_status ON pull_requests(status);
+CREATE INDEX idx_pull_requests_created_at_id ON pull_requests(created_at, pull_request_id);
+CREATE INDEX idx_pull_requests_name_id ON pull_requests(pull_request_name, pull_request_id);
+CREATE INDEX idx_pull_requests_author_created ON pull_requests(author_id, created_at);
+CREATE INDEX idx_pull_requests_status_created ON pull_requests(status, created_at);
+CREATE INDEX idx_pull_requests_merged_at ON pull_requests(merged_at) WHERE merged_at IS NOT NULL;
+CREATE INDEX idx_pull_request_reviewers_reviewer_id ON pull_request_reviewers(reviewer_id);
+CREATE INDEX idx_pull_request_reviewers_pr_id ON pull_request_reviewers(pull_request_id);
+CREATE INDEX idx_pull_request_reviewers_reviewer_pr ON pull_request_reviewers(reviewer_id, pull_request_id);
+CREATE INDEX idx_pull_request_reviewer_changes_pr_id ON pull_request_reviewer_changes(pull_request_id, changed_at);
+CREATE INDEX idx_pull_request_declines_pr_id ON pull_request_declines(pull_request_id);
+CREATE INDEX idx_pull_request_declines_reviewer_id ON pull_request_declines(reviewer_id);
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
@@ -0,0 +1 @@
+ALTER TABLE pull_request_declines DROP COLUMN category;